@@ -32,6 +32,33 @@ var autocompleteCmd = &cobra.Command{
 	},
 }
 
+var widgetKey string
+
+var shellInitCmd = &cobra.Command{
+	Use:       "shell-init bash|zsh|fish [selector]",
+	Short:     "Generate a widget for your shell that inserts the code of a command picked in the command line",
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: tools.ShellWidgetShells(),
+	Long: tools.Logo + `
+The widget is bound to ctrl-g (change it with --key) and opens the listing of commands (of the space
+given, or all of them), inserting the code of the one picked at the cursor instead of running it.
+Load it from your shell's configuration:
+
+  eval "$(cbox shell-init bash)"            # ~/.bashrc
+  eval "$(cbox shell-init zsh)"             # ~/.zshrc
+  cbox shell-init fish | source             # ~/.config/fish/config.fish`,
+	Run: func(cmd *cobra.Command, args []string) {
+		widget, err := tools.ShellWidget(args[0], widgetKey, args[1:]...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(widget)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(autocompleteCmd)
+	rootCmd.AddCommand(shellInitCmd)
+
+	shellInitCmd.Flags().StringVar(&widgetKey, "key", tools.DefaultWidgetKey, "Key the widget is bound to (e.g. ctrl-g or alt-c)")
 }
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"github.com/dplabs/cbox/src/controllers"
 	"github.com/dplabs/cbox/src/tools"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +29,7 @@ var cloudLogoutCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(cloudCmd)
+	cloudCmd.PersistentFlags().BoolVar(&controllers.OfflineFlag, "offline", false, "Answer only with the responses of the cloud cached before, without reaching it")
 	cloudCmd.AddCommand(cloudLoginCmd)
 	cloudCmd.AddCommand(cloudLogoutCmd)
 }
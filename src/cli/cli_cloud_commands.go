@@ -1,18 +1,27 @@
 package cli
 
 import (
+	"strings"
+
 	"github.com/dplabs/cbox/src/controllers"
 	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/console"
 	"github.com/spf13/cobra"
 )
 
 var cloudCommandsListCmd = &cobra.Command{
-	Use:     "list",
+	Use:     "list selector [criteria]",
 	Aliases: []string{"ls", "l"},
-	Args:    cobra.ExactArgs(1),
-	Short:   "Fetch a list of commands from the cloud",
+	Args:    cobra.MinimumNArgs(1),
+	Short:   "Fetch a list of commands from the cloud, optionally only those matching a criteria (see 'cbox search')",
 	Long:    tools.Logo,
-	Run:     func(cmd *cobra.Command, args []string) { ctrl.CloudCommandList(args[0]) },
+	Run: func(cmd *cobra.Command, args []string) {
+		criteria := strings.Join(args[1:], " ")
+		if criteria != "" && !cmd.Flags().Changed("listings-sort") {
+			controllers.ListingsSortOption = console.SortRelevance
+		}
+		ctrl.CloudCommandList(args[0], criteria)
+	},
 }
 
 var cloudCopyCmd = &cobra.Command{
@@ -38,6 +47,7 @@ func init() {
 	cloudCmd.AddCommand(cloudViewCmd)
 
 	cloudCommandsListCmd.Flags().BoolVarP(&controllers.ShowCommandsSourceFlag, "view", "v", false, "Show all details about commands")
-	cloudCopyCmd.Flags().BoolVarP(&controllers.ForceFlag, "force", "f", false, "Force copying commands in case of label clashing with existing ones")
+	cloudViewCmd.Flags().BoolVarP(&controllers.ClipboardFlag, "clipboard", "c", false, "Copy the code (with its variables resolved) to the clipboard instead of printing it")
+	cloudCopyCmd.Flags().BoolVarP(&controllers.ForceFlag, "force", "f", false, "Keep the cloud version of the fields in conflict with existing commands, without asking")
 
 }
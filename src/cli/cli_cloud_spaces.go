@@ -2,6 +2,7 @@ package cli
 
 import (
 	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools"
 	"github.com/spf13/cobra"
 )
 
@@ -27,10 +28,43 @@ var cloudSpaceUnpublishCmd = &cobra.Command{
 	Run:   func(cmd *cobra.Command, args []string) { ctrl.CloudSpaceUnpublish(args[0]) },
 }
 
+var cloudSpaceSyncCmd = &cobra.Command{
+	Use:   "sync space [cloud-selector]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Sync a local space with a cloud space, both ways",
+	Long: tools.Logo + `
+Commands added, changed or deleted on each side since the last sync are applied on the other one. When
+a command changed on both sides, the changes are merged field by field, asking how to resolve the fields
+changed differently on both sides (with --yes, the version updated last is kept). Changes win over
+deletions.
+
+The first time, the cloud space can be given (by default, the one the local space would be published
+to). Later syncs remember it.`,
+	Run: func(cmd *cobra.Command, args []string) { ctrl.CloudSpaceSync(args[0], optionalSelector(args, 1)) },
+}
+
+var cloudSpaceDiffCmd = &cobra.Command{
+	Use:   "diff space [cloud-selector]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Show what would change in the cloud if a local space was published",
+	Long: tools.Logo + `
+Commands are matched by label, printing a unified diff of the code and metadata (description, URL and
+tags) of every command that would be added, changed or removed in the cloud space, and how many of each.
+
+By default, the local space is compared with the cloud space it's synced with or, if never synced, the
+one it would be published to.`,
+	Run: func(cmd *cobra.Command, args []string) { ctrl.CloudSpaceDiff(args[0], optionalSelector(args, 1)) },
+}
+
 func init() {
 	cloudCmd.AddCommand(cloudSpaceInfoCmd)
+	cloudCmd.AddCommand(cloudSpaceDiffCmd)
+	cloudCmd.AddCommand(cloudSpaceSyncCmd)
 	cloudCmd.AddCommand(cloudSpacePublishCmd)
 	cloudCmd.AddCommand(cloudSpaceUnpublishCmd)
 
 	cloudSpacePublishCmd.Flags().StringVarP(&controllers.OrganizationOption, "organization", "o", "", "Publish under this organization")
+	cloudSpaceDiffCmd.Flags().StringVarP(&controllers.OrganizationOption, "organization", "o", "", "Compare with the space of this organization it would be published to")
+	cloudSpaceSyncCmd.Flags().StringVarP(&controllers.OrganizationOption, "organization", "o", "", "Sync with a space of this organization (first sync only)")
+	cloudSpaceSyncCmd.Flags().BoolVar(&controllers.DryRunFlag, "dry-run", false, "Show what would be pushed and pulled without changing anything")
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/models"
 	"github.com/dplabs/cbox/src/tools"
 	"github.com/spf13/cobra"
 )
@@ -20,8 +21,16 @@ var addCmd = &cobra.Command{
 	Aliases: []string{"a"},
 	Args:    cobra.MaximumNArgs(1),
 	Short:   "Add a new command into an space",
-	Long:    tools.Logo,
-	Run:     func(cmd *cobra.Command, args []string) { ctrl.CommandAdd(optionalSelector(args, 0)) },
+	Long: tools.Logo + `
+Values are asked for unless given with flags, so commands can be added from scripts. Code is read from
+the standard input with '--code -':
+
+  echo 'kubectl get pods' | cbox add @work --label pods --tag k8s --code -`,
+	Run: func(cmd *cobra.Command, args []string) {
+		controllers.CommandFieldsOption = changedFields(cmd, commandFieldFlags)
+		controllers.AddTagsOption = tagsOption
+		ctrl.CommandAdd(optionalSelector(args, 0))
+	},
 }
 
 var editCmd = &cobra.Command{
@@ -29,10 +38,38 @@ var editCmd = &cobra.Command{
 	Aliases: []string{"e", "ed"},
 	Args:    cobra.ExactArgs(1),
 	Short:   "Edit a command an existing command",
-	Long:    tools.Logo,
-	Run:     func(cmd *cobra.Command, args []string) { ctrl.CommandEdit(args[0]) },
+	Long: tools.Logo + `
+Values are asked for unless some changes are given with flags, which are applied without confirmation,
+or --editor is given (or 'cbox.edit.editor' is enabled) to edit the command in $EDITOR:
+
+  cbox edit pods@work --set-description 'List pods' --add-tag kubectl --remove-tag old`,
+	Run: func(cmd *cobra.Command, args []string) {
+		controllers.CommandFieldsOption = changedFields(cmd, editFieldFlags)
+		controllers.AddTagsOption = addTagsOption
+		controllers.RemoveTagsOption = removeTagsOption
+		ctrl.CommandEdit(args[0])
+	},
 }
 
+var (
+	commandFieldFlags = map[string]string{
+		"label": models.SearchFieldLabel,
+		"desc":  models.SearchFieldDescription,
+		"url":   models.SearchFieldURL,
+		"code":  models.SearchFieldCode,
+	}
+	editFieldFlags = map[string]string{
+		"set-label":       models.SearchFieldLabel,
+		"set-description": models.SearchFieldDescription,
+		"set-url":         models.SearchFieldURL,
+		"set-code":        models.SearchFieldCode,
+	}
+
+	tagsOption       []string
+	addTagsOption    []string
+	removeTagsOption []string
+)
+
 var deleteCmd = &cobra.Command{
 	Use:     "delete",
 	Aliases: []string{"del"},
@@ -76,6 +113,23 @@ var copyCmd = &cobra.Command{
 	Run:   func(cmd *cobra.Command, args []string) { ctrl.CommandCopy(args[0], optionalSelector(args, 1)) },
 }
 
+var historyCmd = &cobra.Command{
+	Use:     "history",
+	Aliases: []string{"h", "revisions"},
+	Args:    cobra.ExactArgs(1),
+	Short:   "Display all the revisions of a command and the changes between them",
+	Long:    tools.Logo,
+	Run:     func(cmd *cobra.Command, args []string) { ctrl.CommandHistory(args[0]) },
+}
+
+var revertCmd = &cobra.Command{
+	Use:   "revert",
+	Args:  cobra.ExactArgs(2),
+	Short: "Restore the content of a command from one of its revisions",
+	Long:  tools.Logo,
+	Run:   func(cmd *cobra.Command, args []string) { ctrl.CommandRevert(args[0], args[1]) },
+}
+
 func init() {
 	rootCmd.AddCommand(commandsCmd)
 	commandsCmd.AddCommand(addCmd)
@@ -85,9 +139,25 @@ func init() {
 	commandsCmd.AddCommand(tagCmd)
 	commandsCmd.AddCommand(untagCmd)
 	commandsCmd.AddCommand(copyCmd)
+	commandsCmd.AddCommand(historyCmd)
+	commandsCmd.AddCommand(revertCmd)
 
 	commandsCmd.Flags().BoolVarP(&controllers.ShowCommandsSourceFlag, "view", "v", false, "Show all details about commands")
 	viewCmd.Flags().BoolVar(&controllers.SourceOnlyFlag, "src", false, "view only code snippet source code")
+	viewCmd.Flags().BoolVarP(&controllers.ClipboardFlag, "clipboard", "c", false, "Copy the code (with its variables resolved) to the clipboard instead of printing it")
+	viewCmd.Flags().StringArrayVar(&controllers.VariablesOption, "var", []string{}, "Value for a variable of the command (name=value)")
+	addCmd.Flags().String("label", "", "Label of the command")
+	addCmd.Flags().String("desc", "", "Description of the command")
+	addCmd.Flags().String("url", "", "URL of the command")
+	addCmd.Flags().String("code", "", "Code of the command ('-' to read it from the standard input)")
+	addCmd.Flags().StringArrayVar(&tagsOption, "tag", []string{}, "Tag of the command (can be repeated)")
+	editCmd.Flags().String("set-label", "", "New label of the command")
+	editCmd.Flags().String("set-description", "", "New description of the command")
+	editCmd.Flags().String("set-url", "", "New URL of the command")
+	editCmd.Flags().String("set-code", "", "New code of the command ('-' to read it from the standard input)")
+	editCmd.Flags().BoolVar(&controllers.EditorFlag, "editor", false, "Edit the command in $EDITOR as a YAML front matter followed by its code")
+	editCmd.Flags().StringArrayVar(&addTagsOption, "add-tag", []string{}, "Tag to add to the command (can be repeated)")
+	editCmd.Flags().StringArrayVar(&removeTagsOption, "remove-tag", []string{}, "Tag to remove from the command (can be repeated)")
 	copyCmd.Flags().BoolVarP(&controllers.ForceFlag, "force", "f", false, "Force copying commands in case of label clashing with existing ones")
 
 }
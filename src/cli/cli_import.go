@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/cheatsheets"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Args:  cobra.ExactArgs(0),
+	Short: "Import commands into your cbox from other sources",
+	Long:  tools.Logo,
+}
+
+var importHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Pick commands from your shell history and store them into an space",
+	Long:  tools.Logo,
+	Run:   func(cmd *cobra.Command, args []string) { ctrl.ImportHistory(optionalSelector(args, 0)) },
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Args:  cobra.ExactArgs(0),
+	Short: "Export the commands of an space to other cheat-sheet formats",
+	Long:  tools.Logo,
+}
+
+func importCheatsheetCmd(format string) *cobra.Command {
+	return &cobra.Command{
+		Use:   format + " <file|dir> [space]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: fmt.Sprintf("Import the commands found in %s files into an space (created if needed)", format),
+		Long:  tools.Logo,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctrl.ImportCheatsheets(format, args[0], optionalSelector(args, 1))
+		},
+	}
+}
+
+func exportCheatsheetCmd(format string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   format + " <space>",
+		Args:  cobra.ExactArgs(1),
+		Short: fmt.Sprintf("Export the commands of an space as a %s file", format),
+		Long:  tools.Logo,
+		Run:   func(cmd *cobra.Command, args []string) { ctrl.ExportCheatsheet(format, args[0]) },
+	}
+	cmd.Flags().StringVarP(&controllers.ExportFileOption, "file", "f", "", "File to write to (default: standard output)")
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
+	importCmd.AddCommand(importHistoryCmd)
+	for _, format := range cheatsheets.Formats() {
+		importCmd.AddCommand(importCheatsheetCmd(format))
+		exportCmd.AddCommand(exportCheatsheetCmd(format))
+	}
+
+	importHistoryCmd.Flags().StringVar(&controllers.HistoryShellOption, "shell", "", "Shell whose history is imported: bash, zsh or fish (default: current shell)")
+	importHistoryCmd.Flags().StringVar(&controllers.HistoryFileOption, "file", "", "History file to read (default: the shell's default one)")
+	importHistoryCmd.Flags().StringVar(&controllers.SinceOption, "since", "", "Only import commands run since a date (2006-01-02) or for a while (e.g. 7d, 12h)")
+	importHistoryCmd.Flags().StringVar(&controllers.GrepOption, "grep", "", "Only import commands matching this regular expression")
+}
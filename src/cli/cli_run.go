@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:     "run",
+	Aliases: []string{"r", "exec"},
+	Args:    cobra.ExactArgs(1),
+	Short:   "Execute a stored command using your shell",
+	Long:    tools.Logo,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exitCode := ctrl.CommandRun(args[0]); exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	},
+}
+
+var copyCodeCmd = &cobra.Command{
+	Use:     "copy-code",
+	Aliases: []string{"cc", "yank"},
+	Args:    cobra.ExactArgs(1),
+	Short:   "Copy the code of a command to the clipboard",
+	Long: tools.Logo + `
+The code is copied with its variables resolved, using the first clipboard tool available (wl-copy,
+xclip, xsel, pbcopy or clip.exe) or the terminal itself through an OSC 52 escape sequence (always over
+SSH, so the code reaches your local clipboard). If none works, the code is printed.`,
+	Run: func(cmd *cobra.Command, args []string) { ctrl.CommandCopyCode(args[0]) },
+}
+
+var pickCmd = &cobra.Command{
+	Use:   "pick [selector]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Pick a command interactively and print its code",
+	Long: tools.Logo + `
+Nothing else is printed, so it can be used in scripts or shell widgets (see 'cbox shell-init'). Exits
+with 1 if no command is picked.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !ctrl.CommandPick(optionalSelector(args, 0)) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(copyCodeCmd)
+	rootCmd.AddCommand(pickCmd)
+
+	runCmd.Flags().StringVar(&controllers.ShellOption, "shell", "", "Shell used to execute the command (default: $SHELL)")
+	runCmd.Flags().StringArrayVar(&controllers.VariablesOption, "var", []string{}, "Value for a variable of the command (name=value)")
+	copyCodeCmd.Flags().StringArrayVar(&controllers.VariablesOption, "var", []string{}, "Value for a variable of the command (name=value)")
+}
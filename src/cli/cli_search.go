@@ -1,21 +1,42 @@
 package cli
 
 import (
+	"strings"
+
 	"github.com/dplabs/cbox/src/controllers"
 	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/console"
 	"github.com/spf13/cobra"
 )
 
 var searchCmd = &cobra.Command{
-	Use:   "search",
+	Use:   "search [selector] criteria",
 	Args:  cobra.MinimumNArgs(1),
 	Short: "Search commands matching a criteria in a given local space",
-	Long:  tools.Logo,
+	Long: tools.Logo + `
+Criteria is made of terms looked up in the label, tags, description and code of commands, and may
+include typos. Terms can be restricted to a field (label:, desc:, tag:, code: or url:), quoted to
+look for a whole phrase (code:"kubectl apply"), negated (-tag:old or NOT tag:old) and combined with
+AND (implicit) and OR, using parenthesis to group them.
+
+With --regex, criteria is a regular expression instead (see https://github.com/google/re2/wiki/Syntax),
+looked up in the fields given with --field (all of them by default). Case is ignored unless the
+expression contains upper case letters, which can be changed with --case.
+
+Every local space is searched unless a space is given (as selector or with --space), or a namespace
+with --namespace ('name/' for organizations, 'name:' for users). With --remote, cloud spaces listed
+before with 'cbox cloud list' are searched too, using the commands fetched back then.
+
+Results are grouped by space and sorted by relevance unless --listings-sort is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 1 {
-			ctrl.SearchCommands(nil, args[0])
+		if !cmd.Flags().Changed("listings-sort") {
+			controllers.ListingsSortOption = console.SortRelevance
+		}
+
+		if len(args) > 1 && strings.Contains(args[0], "@") {
+			ctrl.SearchCommands(&args[0], strings.Join(args[1:], " "))
 		} else {
-			ctrl.SearchCommands(&args[0], args[1])
+			ctrl.SearchCommands(nil, strings.Join(args, " "))
 		}
 	},
 }
@@ -24,4 +45,11 @@ func init() {
 	rootCmd.AddCommand(searchCmd)
 
 	searchCmd.Flags().BoolVarP(&controllers.ShowCommandsSourceFlag, "view", "v", false, "Show all details about commands")
+	searchCmd.Flags().BoolVarP(&controllers.RegexFlag, "regex", "E", false, "Use criteria as a regular expression")
+	searchCmd.Flags().StringSliceVar(&controllers.SearchFieldsOption, "field", []string{}, "Fields to match the regular expression with: label, description, tag, code or url (default all of them)")
+	searchCmd.Flags().BoolVarP(&controllers.SearchAllFlag, "all", "a", false, "Search every local space")
+	searchCmd.Flags().StringSliceVar(&controllers.SearchSpacesOption, "space", []string{}, "Spaces to search (e.g. 'a,b,org/c')")
+	searchCmd.Flags().StringVar(&controllers.NamespaceOption, "namespace", "", "Search only the spaces of a namespace (e.g. 'org/' or 'user:')")
+	searchCmd.Flags().BoolVarP(&controllers.RemoteFlag, "remote", "r", false, "Search also the cloud spaces cached when listing them")
+	searchCmd.Flags().StringVar(&controllers.CaseOption, "case", "", "Case mode for regular expressions: smart (default), sensitive or insensitive")
 }
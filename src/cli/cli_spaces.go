@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"github.com/dplabs/cbox/src/controllers"
 	"github.com/dplabs/cbox/src/tools"
 	"github.com/spf13/cobra"
 )
@@ -44,4 +45,6 @@ func init() {
 	spacesCmd.AddCommand(spacesCreateCmd)
 	spacesCmd.AddCommand(spacesEditCmd)
 	spacesCmd.AddCommand(spacesDestroyCmd)
+
+	spacesEditCmd.Flags().BoolVar(&controllers.EditorFlag, "editor", false, "Edit the space in $EDITOR as a YAML document")
 }
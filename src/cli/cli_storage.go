@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/spf13/cobra"
+)
+
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Args:  cobra.ExactArgs(0),
+	Short: "Manage how your cbox is stored",
+	Long:  tools.Logo,
+}
+
+var storageMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Args:  cobra.ExactArgs(0),
+	Short: "Copy all your spaces into a different storage backend and start using it",
+	Long:  tools.Logo,
+	Run:   func(cmd *cobra.Command, args []string) { ctrl.StorageMigrate() },
+}
+
+var storageCheckCmd = &cobra.Command{
+	Use:   "check",
+	Args:  cobra.ExactArgs(0),
+	Short: "Validate all your stored spaces, reporting any problem found",
+	Long:  tools.Logo,
+	Run:   func(cmd *cobra.Command, args []string) { ctrl.StorageCheck() },
+}
+
+func init() {
+	rootCmd.AddCommand(storageCmd)
+	storageCmd.AddCommand(storageMigrateCmd)
+	storageCmd.AddCommand(storageCheckCmd)
+
+	storageMigrateCmd.Flags().StringVar(&controllers.StorageBackendOption, "to", "", "Target storage backend (json, sqlite)")
+}
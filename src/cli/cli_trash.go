@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Args:  cobra.ExactArgs(0),
+	Short: "Manage deleted commands and spaces",
+	Long:  tools.Logo,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls", "l"},
+	Args:    cobra.ExactArgs(0),
+	Short:   "List the commands and spaces in the trash",
+	Long:    tools.Logo,
+	Run:     func(cmd *cobra.Command, args []string) { ctrl.TrashList() },
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Args:  cobra.ExactArgs(1),
+	Short: "Restore a deleted command (label@space) or space (@space)",
+	Long:  tools.Logo,
+	Run:   func(cmd *cobra.Command, args []string) { ctrl.TrashRestore(args[0]) },
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Args:  cobra.ExactArgs(0),
+	Short: "Delete permanently the content of the trash",
+	Long:  tools.Logo,
+	Run:   func(cmd *cobra.Command, args []string) { ctrl.TrashEmpty() },
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+
+	trashRestoreCmd.Flags().BoolVarP(&controllers.ForceFlag, "force", "f", false, "Overwrite any command with the same label")
+	trashEmptyCmd.Flags().StringVar(&controllers.OlderThanOption, "older-than", "", "Only delete entries deleted before this long ago (e.g. 30d, 12h)")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dplabs/cbox/src/controllers"
 	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/console"
 	"github.com/dplabs/cbox/src/tools/tty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,27 +21,45 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	setupParameters()
-	cobra.OnInitialize(func() {
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		ctrl = controllers.InitController("")
-		loadParameterValuesFromConfig()
-	})
+		loadParameterValuesFromConfig(cmd)
+		if err := console.CheckOutputFormat(console.OutputFormat); err != nil {
+			log.Fatal(err)
+		}
+		if console.FormatTemplate != "" && console.OutputFormat != "" && console.OutputFormat != console.OutputText {
+			log.Fatal("--format can only be used with text output")
+		}
+	}
 }
 
 func setupParameters() {
 	rootCmd.PersistentFlags().BoolVar(&tty.DisableColors, "no-color", false, "Disable color in the output")
 	rootCmd.PersistentFlags().BoolVar(&tty.DisableOutput, "silent", false, "Completely disable any output")
 	rootCmd.PersistentFlags().BoolVar(&tty.SkipQuestions, "yes", false, "Answer 'yes' to any question")
-	rootCmd.PersistentFlags().StringVarP(&controllers.ListingsModeOption, "listings-mode", "m", "", "Use 'fzf' (interactive) to interact with commands listings or just print them as an static list (static)")
+	rootCmd.PersistentFlags().StringVarP(&controllers.ListingsModeOption, "listings-mode", "m", "", "Use 'fzf' (interactive) or the built-in picker (tui) to interact with commands listings or just print them as an static list (static)")
+	rootCmd.PersistentFlags().StringVar(&console.OutputFormat, "output", "", "Print results as text (default) or in a machine readable format: json, yaml or tsv")
+	rootCmd.PersistentFlags().StringVar(&console.FormatTemplate, "format", "", "Print results using a Go template (e.g. '{{.Label}}\\t{{join .Tags \",\"}}')")
 	rootCmd.PersistentFlags().StringVarP(&controllers.ListingsSortOption, "listings-sort", "s", "", "Sort commands listings by name (default) or date")
 }
 
-func loadParameterValuesFromConfig() {
+func loadParameterValuesFromConfig(cmd *cobra.Command) {
+	controllers.ListingsModeGiven = controllers.ListingsModeOption != ""
 	if controllers.ListingsModeOption == "" {
 		controllers.ListingsModeOption = viper.GetString("cbox.results.mode")
 	}
 	if controllers.ListingsSortOption == "" {
 		controllers.ListingsSortOption = viper.GetString("cbox.results.sort")
 	}
+	if controllers.ShellOption == "" {
+		controllers.ShellOption = viper.GetString("cbox.run.shell")
+	}
+	if !cmd.Flags().Changed("editor") {
+		controllers.EditorFlag = viper.GetBool("cbox.edit.editor")
+	}
+	for _, kind := range console.TemplateKinds() {
+		console.DefaultTemplates[kind] = viper.GetString("cbox.format." + kind)
+	}
 }
 
 func Execute() {
@@ -50,9 +69,7 @@ func Execute() {
 		}
 	} else {
 		if ctrl != nil { // only if the config is initialized
-			if err := viper.WriteConfig(); err != nil {
-				log.Fatal(err)
-			}
+			ctrl.ConfigSave()
 		}
 	}
 }
@@ -1,5 +1,7 @@
 package cli
 
+import "github.com/spf13/cobra"
+
 func optionalSelector(args []string, idx int) *string {
 	if len(args) > idx {
 		return &args[idx]
@@ -7,3 +9,14 @@ func optionalSelector(args []string, idx int) *string {
 		return nil
 	}
 }
+
+// changedFields returns the values of the flags given, keyed by the field each flag sets
+func changedFields(cmd *cobra.Command, flags map[string]string) map[string]string {
+	fields := map[string]string{}
+	for flag, field := range flags {
+		if cmd.Flags().Changed(flag) {
+			fields[field], _ = cmd.Flags().GetString(flag)
+		}
+	}
+	return fields
+}
@@ -12,21 +12,57 @@ var (
 	ListingsModeOption     string
 	ListingsSortOption     string
 	OrganizationOption     string
+	ShellOption            string
+	VariablesOption        []string
+	StorageBackendOption   string
+	OlderThanOption        string
+	HistoryShellOption     string
+	HistoryFileOption      string
+	SinceOption            string
+	GrepOption             string
+	ExportFileOption       string
+	RegexFlag              bool
+	SearchFieldsOption     []string
+	CaseOption             string
+	SearchAllFlag          bool
+	SearchSpacesOption     []string
+	NamespaceOption        string
+	RemoteFlag             bool
+	CommandFieldsOption    map[string]string
+	AddTagsOption          []string
+	RemoveTagsOption       []string
+	EditorFlag             bool
+	ClipboardFlag          bool
+	DryRunFlag             bool
+	OfflineFlag            bool
+	// ListingsModeGiven tells if the listings mode was given in the command line (not taken from config)
+	ListingsModeGiven bool
 )
 
 type CLIController struct {
-	cbox  *models.CBox
-	cloud *models.Cloud
+	cbox   *models.CBox
+	cloud  *models.Cloud
+	loaded bool
 }
 
 func InitController(path string) *CLIController {
-	cbox := core.Load(path)
+	cbox := core.Init(path)
 	cloud := core.CloudClient(cbox)
+	cloud.Offline = OfflineFlag
 
 	controller := CLIController{
-		cbox,
-		cloud,
+		cbox:  cbox,
+		cloud: cloud,
 	}
 
 	return &controller
 }
+
+// box returns the cbox, loading all its spaces the first time it's needed
+func (ctrl *CLIController) box() *models.CBox {
+	if !ctrl.loaded {
+		core.LoadSpaces(ctrl.cbox)
+		ctrl.loaded = true
+	}
+	return ctrl.cbox
+}
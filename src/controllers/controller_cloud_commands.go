@@ -3,6 +3,7 @@ package controllers
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/dplabs/cbox/src/core"
@@ -11,21 +12,51 @@ import (
 	"github.com/dplabs/cbox/src/tools/tty"
 )
 
-func (ctrl *CLIController) CloudCommandList(selectorStr string) {
+func (ctrl *CLIController) CloudCommandList(selectorStr string, criteria string) {
 	selector, err := models.ParseSelectorForCloud(selectorStr)
 	if err != nil {
 		log.Fatalf("cloud: list commands: invalid cloud selector: %v", err)
 	}
 
+	var query *models.Query
+	if criteria != "" {
+		if query, err = models.ParseQuery(criteria); err != nil {
+			log.Fatalf("cloud: list commands: %v", err)
+		}
+	}
+
 	commands, err := ctrl.cloud.CommandList(selector)
 	if err != nil {
 		log.Fatalf("cloud: list commands: %v", err)
 	}
 
-	if ListingsModeOption == "interactive" {
-		ListingsModeOption = "interactive-remote"
+	if selector.Item == "" {
+		ctrl.cacheCloudSpace(selector, commands)
+	}
+
+	if query != nil {
+		commands = query.Search(commands)
+		console.HighlightMatches(query)
+	}
+
+	if ListingsModeOption == "interactive" || ListingsModeOption == "tui" {
+		ListingsModeOption = ListingsModeOption + "-remote"
+	}
+	command, action := console.PrintCommandList(selector.String(), commands, ListingsModeOption, ListingsSortOption)
+	ctrl.handleListingAction(command, action)
+}
+
+// cacheCloudSpace keeps the commands of a cloud space, so they can be found with 'search --remote'
+func (ctrl *CLIController) cacheCloudSpace(selector *models.Selector, commands []*models.Command) {
+	spaceSelector := selector.CloneForItem("")
+	space := models.Space{
+		Meta:    models.Meta{ID: spaceSelector.String(), Selector: spaceSelector},
+		Label:   spaceSelector.Space,
+		Entries: commands,
+	}
+	if err := core.CloudCacheStore(&space); err != nil {
+		console.PrintWarning(fmt.Sprintf("Could not cache cloud space '%s': %v\n", spaceSelector.String(), err))
 	}
-	console.PrintCommandList(selector.String(), commands, ListingsModeOption, ListingsSortOption)
 }
 
 func (ctrl *CLIController) cloneSpace(cloudSelector *models.Selector, commands []*models.Command) {
@@ -40,21 +71,36 @@ func (ctrl *CLIController) cloneSpace(cloudSelector *models.Selector, commands [
 
 	space.Entries = commands
 
-	err = ctrl.cbox.SpaceCreate(space)
+	err = ctrl.box().SpaceCreate(space)
 	for err != nil {
 		console.PrintError("Space already found in your cbox. Try a different one")
 		space.Label = strings.ToLower(console.ReadString("Label", console.NOT_EMPTY_VALUES, console.ONLY_VALID_CHARS))
 		space.Selector.Space = space.Label
 		space.ID = space.Selector.String()
-		err = ctrl.cbox.SpaceCreate(space)
+		err = ctrl.box().SpaceCreate(space)
 	}
 
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
+
+	// record the commands pulled, so later copies and syncs can tell what changed on each side
+	remote := cloudSelector.CloneForItem("")
+	err = core.SyncStateStore(&models.SyncState{
+		Space:    space.Selector.String(),
+		Remote:   remote.String(),
+		SyncedAt: models.UnixTimeNow(),
+		Commands: syncedSpace(space, remote).Entries,
+	})
+	if err != nil {
+		log.Fatalf("cloud: copy commands: %v", err)
+	}
 
 	console.PrintSuccess(fmt.Sprintf("Space cloned successfully into '%s'!", space.Selector.String()))
 }
 
-func (ctrl *CLIController) copyCommands(spaceSelector *models.Selector, commands []*models.Command) {
+// copyCommands copies commands from the cloud into a local space, merging field by field the ones already
+// found there. When the space is synced with the cloud space they come from, their versions last pulled
+// are used to tell which side changed each field (and are updated afterwards)
+func (ctrl *CLIController) copyCommands(cloudSelector *models.Selector, spaceSelector *models.Selector, commands []*models.Command) {
 
 	console.PrintInfo(fmt.Sprintf("Copying commands into existing space '%s'...\n", spaceSelector.String()))
 
@@ -63,16 +109,55 @@ func (ctrl *CLIController) copyCommands(spaceSelector *models.Selector, commands
 		log.Fatalf("cloud: copy command: %v", err)
 	}
 
+	remote := cloudSelector.CloneForItem("")
+	state, err := core.SyncStateFind(space.Selector)
+	if err != nil {
+		log.Fatalf("cloud: copy command: %v", err)
+	}
+	if state != nil && state.Remote != remote.String() {
+		state = nil
+	}
+
+	bases := map[string]*models.Command{}
+	if state != nil {
+		for _, command := range state.Commands {
+			bases[command.Label] = command
+		}
+	}
+
 	failures := false
 	for _, command := range commands {
-		err = space.CommandAdd(command, ForceFlag)
+		existing, err := space.CommandFind(command.Label)
+		if err != nil {
+			err = space.CommandAdd(command, false)
+		} else {
+			err = ctrl.mergeCommand(space, existing, models.MergeCommand(bases[command.Label], existing, command))
+		}
 		if err != nil {
 			failures = true
 			log.Printf("cloud: copy command: %v", err)
+			continue
+		}
+
+		if state != nil {
+			base := syncedCommand(command, remote)
+			base.Revisions = nil
+			bases[command.Label] = base
 		}
 	}
 
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
+
+	if state != nil {
+		state.Commands = []*models.Command{}
+		for _, base := range bases {
+			state.Commands = append(state.Commands, base)
+		}
+		sort.Slice(state.Commands, func(i, j int) bool { return state.Commands[i].Label < state.Commands[j].Label })
+		if err := core.SyncStateStore(state); err != nil {
+			log.Fatalf("cloud: copy command: %v", err)
+		}
+	}
 
 	if failures {
 		console.PrintError("Some commands could not be stored")
@@ -81,6 +166,28 @@ func (ctrl *CLIController) copyCommands(spaceSelector *models.Selector, commands
 	}
 }
 
+// mergeCommand applies to a local command the changes merged from its cloud version, asking how to
+// resolve the conflicts found (if any)
+func (ctrl *CLIController) mergeCommand(space *models.Space, command *models.Command, merge *models.CommandMerge) error {
+	resolved := merge.Merged
+	if !merge.Resolved() {
+		var err error
+		if _, resolved, err = ctrl.resolveConflicts(merge, models.MergeMine); err != nil {
+			return err
+		}
+	}
+	return ctrl.updateCommand(space, command, resolved)
+}
+
+// resolveConflicts asks how to resolve the conflicts found merging a command, keeping the cloud version of
+// the fields in conflict without asking when forced
+func (ctrl *CLIController) resolveConflicts(merge *models.CommandMerge, defaultResolution string) (string, *models.Command, error) {
+	if ForceFlag {
+		return models.MergeTheirs, merge.Resolve(models.MergeTheirs), nil
+	}
+	return console.ResolveConflicts(merge, defaultResolution)
+}
+
 func (ctrl *CLIController) CloudCopy(cloudSelectorStr string, spcSelectorStr *string) {
 	console.PrintAction("Copying cloud commands")
 
@@ -124,7 +231,7 @@ func (ctrl *CLIController) CloudCopy(cloudSelectorStr string, spcSelectorStr *st
 		if cloneRemoteSpace {
 			ctrl.cloneSpace(cloudSelector, commands)
 		} else {
-			ctrl.copyCommands(spaceSelector, commands)
+			ctrl.copyCommands(cloudSelector, spaceSelector, commands)
 		}
 	} else {
 		console.PrintError("Cloning cancelled")
@@ -146,5 +253,10 @@ func (ctrl *CLIController) CloudCommandView(selectorStr string) {
 		log.Fatalf("cloud: view command: %v", err)
 	}
 
+	if ClipboardFlag {
+		ctrl.copyCode(command)
+		return
+	}
+
 	console.PrintCommand(selector.String(), command, false)
 }
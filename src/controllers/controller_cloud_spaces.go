@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
@@ -78,7 +79,7 @@ func (ctrl *CLIController) CloudSpacePublish(spcSelectorStr string) {
 
 		ctrl.cleanOldSpaceFile(space, selector)
 
-		core.Save(ctrl.cbox) // to store space's new namespace
+		core.Save(ctrl.box()) // to store space's new namespace
 
 		console.PrintSuccess("Space published successfully!")
 	} else {
@@ -116,3 +117,236 @@ func (ctrl *CLIController) CloudSpaceUnpublish(spcSelectorStr string) {
 		console.PrintError("Unpublishing cancelled")
 	}
 }
+
+// CloudSpaceSync brings a local space and its cloud counterpart in sync: commands added, changed or
+// deleted on one side since the last sync are applied on the other one
+func (ctrl *CLIController) CloudSpaceSync(spcSelectorStr string, cloudSelectorStr *string) {
+	console.PrintAction("Syncing an space")
+
+	if ctrl.cloud.Offline {
+		log.Fatalf("cloud: sync space: can't sync while %v", models.ErrCloudOffline)
+	}
+	// syncing with stale commands would overwrite the latest changes, so whatever was cached is revalidated
+	ctrl.cloud.CacheTTL = 0
+
+	selector, err := models.ParseSelectorMandatorySpace(spcSelectorStr)
+	if err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	}
+
+	space, err := ctrl.findSpace(selector)
+	if err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	}
+
+	state, err := core.SyncStateFind(space.Selector)
+	if err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	}
+
+	remote, err := ctrl.syncRemote(space, state, cloudSelectorStr)
+	if err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	}
+
+	tty.Print("Syncing '%s' with '%s'...\n\n", space.Selector.String(), remote.String())
+
+	published := true
+	remoteCommands := []*models.Command{}
+	if _, err := ctrl.cloud.SpaceFind(remote); errors.Is(err, models.ErrCloudNotFound) && state == nil {
+		published = false
+	} else if err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	} else if remoteCommands, err = ctrl.cloud.CommandList(remote); err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	}
+
+	plan := models.PlanSync(state, space.Entries, remoteCommands)
+
+	header := fmt.Sprintf("%s <-> %s", space.Selector.String(), remote.String())
+	if DryRunFlag {
+		console.PrintSyncPlan(header, plan, true)
+		return
+	}
+
+	for _, conflict := range plan.Conflicts {
+		resolution, command, err := ctrl.resolveConflicts(conflict.Merge, conflict.Merge.Newest())
+		if err != nil {
+			log.Fatalf("cloud: sync space: %v", err)
+		}
+		plan.Resolve(conflict, resolution, command)
+	}
+
+	for _, change := range plan.Pull {
+		if change.Kind == models.SyncDeleted {
+			command, err := space.CommandFind(change.Label)
+			if err != nil {
+				log.Fatalf("cloud: sync space: %v", err)
+			}
+			core.Trash(models.NewCommandTrashEntry(command))
+			space.CommandDelete(command)
+			continue
+		}
+
+		// commands found locally are updated, so their revisions are kept
+		if existing, err := space.CommandFind(change.Label); err == nil {
+			err = ctrl.updateCommand(space, existing, change.Command)
+			if err != nil {
+				log.Fatalf("cloud: sync space: %v", err)
+			}
+			continue
+		}
+
+		command := syncedCommand(change.Command, space.Selector)
+		if err := space.CommandAdd(command, false); err != nil {
+			log.Fatalf("cloud: sync space: %v", err)
+		}
+	}
+
+	synced := syncedSpace(space, remote)
+	if len(plan.Push) != 0 || !published {
+		if err := ctrl.cloud.SpacePublish(synced); err != nil {
+			log.Fatalf("cloud: sync space: %v", err)
+		}
+	}
+
+	core.Save(ctrl.box())
+
+	syncs := 1
+	if state != nil {
+		syncs = state.Syncs + 1
+	}
+	err = core.SyncStateStore(&models.SyncState{
+		Space:    space.Selector.String(),
+		Remote:   remote.String(),
+		Syncs:    syncs,
+		SyncedAt: models.UnixTimeNow(),
+		Commands: synced.Entries,
+	})
+	if err != nil {
+		log.Fatalf("cloud: sync space: %v", err)
+	}
+
+	console.PrintSyncPlan(header, plan, false)
+	console.PrintSuccess(fmt.Sprintf("Space synced successfully! (sync #%d: %d pushed, %d pulled, %d conflicts)", syncs, len(plan.Push), len(plan.Pull), len(plan.Conflicts)))
+}
+
+// CloudSpaceDiff shows what would change in the cloud if a local space was published: the commands it
+// would add, change or remove there
+func (ctrl *CLIController) CloudSpaceDiff(spcSelectorStr string, cloudSelectorStr *string) {
+	selector, err := models.ParseSelectorMandatorySpace(spcSelectorStr)
+	if err != nil {
+		log.Fatalf("cloud: diff space: %v", err)
+	}
+
+	space, err := ctrl.findSpace(selector)
+	if err != nil {
+		log.Fatalf("cloud: diff space: %v", err)
+	}
+
+	var remote *models.Selector
+	if cloudSelectorStr != nil {
+		remote, err = models.ParseSelectorForCloud(*cloudSelectorStr)
+	} else {
+		var state *models.SyncState
+		if state, err = core.SyncStateFind(space.Selector); err == nil {
+			remote, err = ctrl.syncRemote(space, state, nil)
+		}
+	}
+	if err != nil {
+		log.Fatalf("cloud: diff space: %v", err)
+	}
+	remote = remote.CloneForItem("")
+
+	// compare with the latest commands, unless offline
+	ctrl.cloud.CacheTTL = 0
+
+	published := true
+	remoteCommands := []*models.Command{}
+	if _, err := ctrl.cloud.SpaceFind(remote); errors.Is(err, models.ErrCloudNotFound) {
+		published = false
+	} else if err != nil {
+		log.Fatalf("cloud: diff space: %v", err)
+	} else if remoteCommands, err = ctrl.cloud.CommandList(remote); err != nil {
+		log.Fatalf("cloud: diff space: %v", err)
+	}
+
+	localCommands := space.Entries
+	if selector.Item != "" {
+		localCommands = space.CommandList(selector.Item)
+		remoteCommands = (&models.Space{Entries: remoteCommands}).CommandList(selector.Item)
+	}
+
+	diff := models.DiffSpace(space.Selector, remote, localCommands, remoteCommands)
+	diff.Published = published
+
+	console.PrintSpaceDiff(fmt.Sprintf("%s -> %s", space.Selector.String(), remote.String()), diff)
+}
+
+// syncRemote returns the cloud space a local space is synced with: the one given, the one it was synced
+// with before or, the first time, the one it would be published to
+func (ctrl *CLIController) syncRemote(space *models.Space, state *models.SyncState, cloudSelectorStr *string) (*models.Selector, error) {
+	if cloudSelectorStr != nil {
+		remote, err := models.ParseSelectorForCloud(*cloudSelectorStr)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil && state.Remote != remote.String() {
+			return nil, fmt.Errorf("space already synced with '%s'", state.Remote)
+		}
+		return remote, nil
+	}
+
+	if state != nil {
+		return models.ParseSelectorForCloud(state.Remote)
+	}
+
+	if OrganizationOption != "" {
+		return models.NewSelector(models.TypeOrganization, OrganizationOption, space.Label, ""), nil
+	}
+	if space.Selector.NamespaceType != models.TypeNone {
+		return space.Selector.CloneForItem(""), nil
+	}
+	if ctrl.cloud.Login == "" {
+		return nil, fmt.Errorf("not logged in the cloud (see 'cbox cloud login')")
+	}
+	return models.NewSelector(models.TypeUser, ctrl.cloud.Login, space.Label, ""), nil
+}
+
+// updateCommand applies the content (code, description, URL and tags) of a different version of a command
+// to the local one, recording it as a new revision
+func (ctrl *CLIController) updateCommand(space *models.Space, command *models.Command, content *models.Command) error {
+	if models.SameContent(content, command) {
+		return nil
+	}
+
+	previous := *command
+	command.Code = content.Code
+	command.Description = content.Description
+	command.URL = content.URL
+	command.Tags = append([]string{}, content.Tags...)
+	return space.CommandEdit(command, previous, ctrl.author())
+}
+
+// syncedSpace copies a local space as it's published in the cloud, without the revisions of its commands
+func syncedSpace(space *models.Space, remote *models.Selector) *models.Space {
+	synced := *space
+	synced.Selector = remote.CloneForItem("")
+	synced.ID = synced.Selector.String()
+	synced.Entries = []*models.Command{}
+	for _, command := range space.Entries {
+		entry := syncedCommand(command, remote)
+		entry.Revisions = nil
+		synced.Entries = append(synced.Entries, entry)
+	}
+	return &synced
+}
+
+// syncedCommand copies a command into another space, keeping when it was created and updated
+func syncedCommand(command *models.Command, space *models.Selector) *models.Command {
+	synced := *command
+	synced.Tags = append([]string{}, command.Tags...)
+	synced.Selector = space.CloneForItem(command.Label)
+	synced.ID = synced.Selector.String()
+	return &synced
+}
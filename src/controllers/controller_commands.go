@@ -3,6 +3,7 @@ package controllers
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/dplabs/cbox/src/core"
@@ -30,7 +31,8 @@ func (ctrl *CLIController) CommandList(spcSelectorStr *string) {
 
 	commands := space.CommandList(selector.Item)
 
-	console.PrintCommandList(selector.String(), commands, ListingsModeOption, ListingsSortOption)
+	command, action := console.PrintCommandList(selector.String(), commands, ListingsModeOption, ListingsSortOption)
+	ctrl.handleListingAction(command, action)
 }
 
 func (ctrl *CLIController) CommandAdd(spcSelectorStr *string) {
@@ -51,6 +53,23 @@ func (ctrl *CLIController) CommandAdd(spcSelectorStr *string) {
 		log.Fatalf("add command: %v", err)
 	}
 
+	if commandOptionsGiven() {
+		command := &models.Command{Tags: []string{}}
+		if err := applyCommandOptions(command); err != nil {
+			log.Fatalf("add command: %v", err)
+		}
+		command.Selector = space.Selector.CloneForItem(command.Label)
+
+		if err := space.CommandAdd(command, false); err != nil {
+			log.Fatalf("%v", err)
+		}
+		core.Save(ctrl.box())
+
+		console.PrintCommand("New command", command, false)
+		console.PrintSuccess("Command stored successfully!")
+		return
+	}
+
 	tty.Print("Data for new command:\n")
 
 	command := console.ReadCommand(space)
@@ -61,7 +80,7 @@ func (ctrl *CLIController) CommandAdd(spcSelectorStr *string) {
 		command.Label = strings.ToLower(console.ReadString("Label", console.NOT_EMPTY_VALUES, console.ONLY_VALID_CHARS))
 		err = space.CommandAdd(command, false)
 	}
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
 
 	console.PrintCommand("New command", command, false)
 
@@ -81,25 +100,53 @@ func (ctrl *CLIController) CommandEdit(cmdSelectorStr string) {
 		log.Fatalf("edit command: %v", err)
 	}
 
-	previousCommandLabel := command.Label
+	previous := snapshot(command)
+
+	if commandOptionsGiven() {
+		if err := applyCommandOptions(command); err != nil {
+			log.Fatalf("edit command: %v", err)
+		}
+		command.Selector.Item = command.Label
+
+		if err := space.CommandEdit(command, previous, ctrl.author()); err != nil {
+			log.Fatalf("edit command: %v", err)
+		}
+		core.Save(ctrl.box())
+
+		console.PrintCommand("Command after edition", command, false)
+		console.PrintSuccess("Command updated successfully!")
+		return
+	}
 
 	console.PrintCommand("Command to edit", command, false)
 
-	console.EditCommand(command)
+	if EditorFlag {
+		err := console.EditCommandInEditor(command, func(edited *models.Command) error {
+			if edited.Label != previous.Label && commandLabelInUse(space, edited.Label) {
+				return fmt.Errorf("label '%s' already found in space", edited.Label)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("edit command: %v", err)
+		}
+	} else {
+		console.EditCommand(command)
+	}
 	command.Selector.Item = command.Label
 
-	err = space.CommandEdit(command, previousCommandLabel)
+	err = space.CommandEdit(command, previous, ctrl.author())
 	for err != nil {
 		console.PrintError(fmt.Sprintf("Label '%s' already found in space. Try a different one", command.Label))
 		command.Label = strings.ToLower(console.ReadString("Label", console.NOT_EMPTY_VALUES, console.ONLY_VALID_CHARS))
 		command.Selector.Item = command.Label
-		err = space.CommandEdit(command, previousCommandLabel)
+		err = space.CommandEdit(command, previous, ctrl.author())
 	}
 
 	console.PrintCommand("Command after edition", command, false)
 
 	if tty.Confirm("Update?") {
-		core.Save(ctrl.cbox)
+		core.Save(ctrl.box())
 		console.PrintSuccess("Command updated successfully!")
 	} else {
 		console.PrintError("Edition cancelled")
@@ -122,9 +169,10 @@ func (ctrl *CLIController) CommandDelete(cmdSelectorStr string) {
 	console.PrintCommand("Command to delete ", command, false)
 
 	if tty.Confirm("Are you sure you want to delete this command?") {
+		core.Trash(models.NewCommandTrashEntry(command))
 		space.CommandDelete(command)
-		core.Save(ctrl.cbox)
-		console.PrintSuccess("Command deleted successfully!")
+		core.Save(ctrl.box())
+		console.PrintSuccess(fmt.Sprintf("Command deleted successfully! (restore it with 'cbox trash restore %s')", command.Selector.String()))
 	} else {
 		console.PrintError("Deletion cancelled")
 	}
@@ -141,6 +189,21 @@ func (ctrl *CLIController) CommandView(cmdSelectorStr string) {
 		log.Fatalf("view command: %v", err)
 	}
 
+	if SourceOnlyFlag || len(VariablesOption) != 0 {
+		code, err := ctrl.resolveCode(command)
+		if err != nil {
+			log.Fatalf("view command: %v", err)
+		}
+		resolved := *command
+		resolved.Code = code
+		command = &resolved
+	}
+
+	if ClipboardFlag {
+		ctrl.copyCode(command)
+		return
+	}
+
 	console.PrintCommand(command.Selector.String(), command, SourceOnlyFlag)
 }
 
@@ -183,9 +246,104 @@ func (ctrl *CLIController) CommandCopy(cmdSelectorStr string, spcSelectorStr *st
 			log.Fatalf("copy command: %v", err)
 		}
 
-		core.Save(ctrl.cbox)
+		core.Save(ctrl.box())
 		console.PrintSuccess("Command copied successfully!")
 	} else {
 		console.PrintError("Copy cancelled")
 	}
 }
+
+func (ctrl *CLIController) CommandHistory(cmdSelectorStr string) {
+	selector, err := models.ParseSelector(cmdSelectorStr)
+	if err != nil {
+		log.Fatalf("command history: %v", err)
+	}
+
+	_, command, err := ctrl.findSpaceAndCommand(selector)
+	if err != nil {
+		log.Fatalf("command history: %v", err)
+	}
+
+	console.PrintCommandHistory(command.Selector.String(), command)
+}
+
+func (ctrl *CLIController) CommandRevert(cmdSelectorStr string, revisionStr string) {
+	console.PrintAction("Reverting a command")
+
+	selector, err := models.ParseSelector(cmdSelectorStr)
+	if err != nil {
+		log.Fatalf("revert command: %v", err)
+	}
+
+	revision, err := strconv.Atoi(revisionStr)
+	if err != nil {
+		log.Fatalf("revert command: invalid revision '%s'", revisionStr)
+	}
+
+	space, command, err := ctrl.findSpaceAndCommand(selector)
+	if err != nil {
+		log.Fatalf("revert command: %v", err)
+	}
+
+	err = space.CommandRevert(command, revision, ctrl.author())
+	if err != nil {
+		log.Fatalf("revert command: %v", err)
+	}
+
+	console.PrintCommand(fmt.Sprintf("Command after reverting to revision %d", revision), command, false)
+
+	if tty.Confirm("Update?") {
+		core.Save(ctrl.box())
+		console.PrintSuccess("Command reverted successfully!")
+	} else {
+		console.PrintError("Revert cancelled")
+	}
+}
+
+// commandOptionsGiven checks if any field of a command has been given with flags, so it's not asked for
+func commandOptionsGiven() bool {
+	return len(CommandFieldsOption) != 0 || len(AddTagsOption) != 0 || len(RemoveTagsOption) != 0
+}
+
+// applyCommandOptions sets the fields and tags given with flags into a command, reading the code from
+// the standard input if given as '-'
+func applyCommandOptions(command *models.Command) error {
+	for field, value := range CommandFieldsOption {
+		switch field {
+		case models.SearchFieldLabel:
+			command.Label = strings.ToLower(strings.TrimSpace(value))
+		case models.SearchFieldDescription:
+			command.Description = strings.TrimSpace(value)
+		case models.SearchFieldURL:
+			command.URL = strings.TrimSpace(value)
+		case models.SearchFieldCode:
+			if value == "-" {
+				stdin, err := tty.ReadAll()
+				if err != nil {
+					return fmt.Errorf("could not read code from standard input: %v", err)
+				}
+				value = stdin
+			}
+			command.Code = strings.TrimRight(value, "\r\n")
+		default:
+			return fmt.Errorf("unknown field '%s'", field)
+		}
+	}
+
+	for _, tag := range RemoveTagsOption {
+		command.TagDelete(tag)
+	}
+	for _, tag := range AddTagsOption {
+		if err := console.ValidateTag(tag); err != nil {
+			return err
+		}
+		command.TagAdd(tag)
+	}
+
+	return console.ValidateCommand(command)
+}
+
+func commandLabelInUse(space *models.Space, label string) bool {
+	_, err := space.CommandFind(label)
+	return err == nil
+}
@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"log"
+
+	"github.com/dplabs/cbox/src/core"
 	"github.com/dplabs/cbox/src/tools/console"
 	"github.com/spf13/viper"
 )
@@ -14,3 +17,9 @@ func (ctrl *CLIController) ConfigGet(config string) {
 	value := viper.GetString(config)
 	console.PrintSetting(config, value)
 }
+
+func (ctrl *CLIController) ConfigSave() {
+	if err := core.SaveSettings(); err != nil {
+		log.Fatalf("config: save: %v", err)
+	}
+}
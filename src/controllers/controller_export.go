@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/cheatsheets"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+func (ctrl *CLIController) ExportCheatsheet(format string, spcSelectorStr string) {
+	cheatsheet, err := cheatsheets.Get(format)
+	if err != nil {
+		log.Fatalf("export %s: %v", format, err)
+	}
+
+	selector, err := models.ParseSelectorMandatorySpace(spcSelectorStr)
+	if err != nil {
+		log.Fatalf("export %s: %v", format, err)
+	}
+
+	space, err := ctrl.findSpace(selector)
+	if err != nil {
+		log.Fatalf("export %s: %v", format, err)
+	}
+
+	var out bytes.Buffer
+	if err := cheatsheet.Export(&out, space); err != nil {
+		log.Fatalf("export %s: %v", format, err)
+	}
+
+	if ExportFileOption == "" {
+		tty.Print("%s", out.String())
+		return
+	}
+
+	if err := ioutil.WriteFile(ExportFileOption, out.Bytes(), 0644); err != nil {
+		log.Fatalf("export %s: %v", format, err)
+	}
+	console.PrintSuccess(fmt.Sprintf("%d commands exported successfully to '%s'!", len(space.Entries), ExportFileOption))
+}
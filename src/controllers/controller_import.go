@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dplabs/cbox/src/core"
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/cheatsheets"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/history"
+	"github.com/dplabs/cbox/src/tools/tty"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+const sinceDateFormat = "2006-01-02"
+
+func (ctrl *CLIController) ImportHistory(spcSelectorStr *string) {
+	console.PrintAction("Importing commands from shell history")
+
+	s := ""
+	if spcSelectorStr != nil {
+		s = *spcSelectorStr
+	}
+
+	selector, err := models.ParseSelector(s)
+	if err != nil {
+		log.Fatalf("import history: %v", err)
+	}
+
+	space, err := ctrl.findSpace(selector)
+	if err != nil {
+		log.Fatalf("import history: %v", err)
+	}
+
+	shell := HistoryShellOption
+	if shell == "" {
+		shell = path.Base(os.Getenv("SHELL"))
+	}
+
+	historyFile := HistoryFileOption
+	if historyFile == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			log.Fatalf("import history: could not get HOME: %v", err)
+		}
+		historyFile, err = history.DefaultFile(shell, home)
+		if err != nil {
+			log.Fatalf("import history: %v (use --shell)", err)
+		}
+	}
+
+	since, err := parseSince(SinceOption)
+	if err != nil {
+		log.Fatalf("import history: %v", err)
+	}
+
+	var grep *regexp.Regexp
+	if GrepOption != "" {
+		grep, err = regexp.Compile(GrepOption)
+		if err != nil {
+			log.Fatalf("import history: invalid --grep expression: %v", err)
+		}
+	}
+
+	f, err := os.Open(historyFile)
+	if err != nil {
+		log.Fatalf("import history: %v", err)
+	}
+	defer f.Close()
+
+	entries, err := history.Parse(shell, f)
+	if err != nil {
+		log.Fatalf("import history: could not read '%s': %v", historyFile, err)
+	}
+	entries = history.Filter(history.Dedup(entries), since, grep)
+
+	candidates := []string{}
+	for _, entry := range entries {
+		if !commandCodePresentInSpace(space, entry.Command) {
+			candidates = append(candidates, entry.Command)
+		}
+	}
+
+	if len(candidates) == 0 {
+		tty.Print("No new commands found in '%s'\n", historyFile)
+		return
+	}
+
+	var selected []string
+	if ListingsModeOption == "interactive" || ListingsModeOption == "tui" {
+		selected = console.SelectEntries(fmt.Sprintf("Commands to import into '%s' (TAB to select)", space.String()), candidates, ListingsModeOption)
+	} else {
+		for _, candidate := range candidates {
+			tty.Print("%s\n", candidate)
+		}
+		if tty.Confirm(fmt.Sprintf("Import these %d commands into space '%s'?", len(candidates), space.String())) {
+			selected = candidates
+		}
+	}
+
+	if len(selected) == 0 {
+		console.PrintError("Import cancelled")
+		return
+	}
+
+	for _, code := range selected {
+		command := models.Command{
+			Label:       importedLabel(space, code),
+			Code:        code,
+			Description: fmt.Sprintf("Imported from %s history", shell),
+			Tags:        []string{},
+		}
+		command.Selector = space.Selector.CloneForItem(command.Label)
+
+		if err := space.CommandAdd(&command, false); err != nil {
+			log.Fatalf("import history: %v", err)
+		}
+		tty.Print("%s %s\n", command.Selector.String(), tty.ColorBoldBlack("<- "+strings.SplitN(code, "\n", 2)[0]))
+	}
+
+	core.Save(ctrl.box())
+
+	console.PrintSuccess(fmt.Sprintf("%d commands imported successfully!", len(selected)))
+}
+
+// parseSince accepts either how long ago (7d, 12h) or a date (2006-01-02)
+func parseSince(since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.ParseInLocation(sinceDateFormat, since, time.Local); err == nil {
+		return date, nil
+	}
+	duration, err := tools.ParseDuration(since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since value '%s': use a date (%s) or a duration (7d, 12h)", since, sinceDateFormat)
+	}
+	return time.Now().Add(-duration), nil
+}
+
+func commandCodePresentInSpace(space *models.Space, code string) bool {
+	for _, command := range space.Entries {
+		if strings.TrimSpace(command.Code) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// importedLabel generates a label for an imported command out of some text, unique within the space
+func importedLabel(space *models.Space, text string) string {
+	label := tools.GenerateLabel(text, func(label string) bool {
+		_, err := space.CommandFind(label)
+		return err == nil
+	})
+
+	if !console.CheckValidChars(label) {
+		log.Fatalf("import: could not generate a valid label for '%s'", text)
+	}
+	return label
+}
+
+func (ctrl *CLIController) ImportCheatsheets(format string, source string, spcSelectorStr *string) {
+	console.PrintAction(fmt.Sprintf("Importing commands from %s files", format))
+
+	cheatsheet, err := cheatsheets.Get(format)
+	if err != nil {
+		log.Fatalf("import %s: %v", format, err)
+	}
+
+	files, err := cheatsheetFiles(source, cheatsheet.Extension())
+	if err != nil {
+		log.Fatalf("import %s: %v", format, err)
+	}
+
+	space := ctrl.importTargetSpace(spcSelectorStr, fmt.Sprintf("Imported from %s", format))
+
+	imported := 0
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			log.Fatalf("import %s: %v", format, err)
+		}
+		content, err := cheatsheet.Import(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("import %s: '%s': %v", format, file, err)
+		}
+
+		for _, command := range content.Entries {
+			if commandCodePresentInSpace(space, strings.TrimSpace(command.Code)) {
+				continue
+			}
+
+			labelSource := command.Description
+			if labelSource == "" {
+				labelSource = command.Code
+			}
+			command.Label = importedLabel(space, labelSource)
+			command.Selector = space.Selector.CloneForItem(command.Label)
+
+			if err := space.CommandAdd(command, false); err != nil {
+				log.Fatalf("import %s: %v", format, err)
+			}
+			tty.Print("%s %s\n", command.Selector.String(), tty.ColorBoldBlack("<- "+path.Base(file)))
+			imported++
+		}
+	}
+
+	core.Save(ctrl.box())
+
+	console.PrintSuccess(fmt.Sprintf("%d commands imported successfully into space '%s'!", imported, space.String()))
+}
+
+// cheatsheetFiles returns the file given or, for directories, all the files within with the extension given
+func cheatsheetFiles(source string, extension string) ([]string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{source}, nil
+	}
+
+	files := []string{}
+	err = filepath.Walk(source, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(file) == extension {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no '%s' files found in '%s'", extension, source)
+	}
+	return files, nil
+}
+
+// importTargetSpace finds the space to import commands into, creating it if it does not exist yet
+func (ctrl *CLIController) importTargetSpace(spcSelectorStr *string, description string) *models.Space {
+	s := ""
+	if spcSelectorStr != nil {
+		s = *spcSelectorStr
+	}
+
+	selector, err := models.ParseSelector(s)
+	if err != nil {
+		log.Fatalf("import: %v", err)
+	}
+
+	space, err := ctrl.findSpace(selector)
+	if err == nil {
+		return space
+	}
+	if spcSelectorStr == nil || selector.NamespaceType != models.TypeNone {
+		log.Fatalf("import: %v", err)
+	}
+	if !console.CheckValidChars(selector.Space) {
+		log.Fatalf("import: invalid characters in space label '%s'", selector.Space)
+	}
+
+	space = &models.Space{
+		Label:       selector.Space,
+		Description: description,
+	}
+	space.Selector = models.NewSelector(models.TypeNone, "", space.Label, "")
+
+	if err := ctrl.box().SpaceCreate(space); err != nil {
+		log.Fatalf("import: %v", err)
+	}
+	tty.Print("Space '%s' created\n", space.String())
+
+	return space
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+func (ctrl *CLIController) CommandRun(cmdSelectorStr string) int {
+	selector, err := models.ParseSelectorMandatoryItem(cmdSelectorStr)
+	if err != nil {
+		log.Fatalf("run command: %v", err)
+	}
+
+	_, command, err := ctrl.findSpaceAndCommand(selector)
+	if err != nil {
+		log.Fatalf("run command: %v", err)
+	}
+
+	return ctrl.runCommand(command)
+}
+
+func (ctrl *CLIController) runCommand(command *models.Command) int {
+	code, err := ctrl.resolveCode(command)
+	if err != nil {
+		log.Fatalf("run command: '%s': %v", command.Selector.String(), err)
+	}
+
+	exitCode, err := tools.RunSnippet(tools.ResolveShell(ShellOption), code)
+	if err != nil {
+		log.Fatalf("run command: '%s': %v", command.Selector.String(), err)
+	}
+	return exitCode
+}
+
+func (ctrl *CLIController) CommandCopyCode(cmdSelectorStr string) {
+	selector, err := models.ParseSelectorMandatoryItem(cmdSelectorStr)
+	if err != nil {
+		log.Fatalf("copy code: %v", err)
+	}
+
+	_, command, err := ctrl.findSpaceAndCommand(selector)
+	if err != nil {
+		log.Fatalf("copy code: %v", err)
+	}
+
+	ctrl.copyCode(command)
+}
+
+// CommandPick lets the user pick a command of a space (or of every one if none given) and prints its code,
+// so shell widgets can insert it in the command line. It returns false if nothing was picked
+func (ctrl *CLIController) CommandPick(spcSelectorStr *string) bool {
+	commands := []*models.Command{}
+	header := "Pick a command"
+	if spcSelectorStr != nil {
+		selector, err := models.ParseSelector(*spcSelectorStr)
+		if err != nil {
+			log.Fatalf("pick command: %v", err)
+		}
+
+		space, err := ctrl.findSpace(selector)
+		if err != nil {
+			log.Fatalf("pick command: %v", err)
+		}
+		commands = space.CommandList(selector.Item)
+		header = selector.String()
+	} else {
+		for _, space := range ctrl.box().Spaces {
+			commands = append(commands, space.CommandList("")...)
+		}
+	}
+
+	// fzf is only required if asked for explicitly: the built-in picker is used otherwise when it's missing
+	listingMode := ListingsModeOption
+	if listingMode == "interactive" && !ListingsModeGiven {
+		listingMode = ""
+	}
+
+	command := console.PickCommand(header, commands, listingMode, ListingsSortOption)
+	if command == nil {
+		return false
+	}
+
+	tty.Print("%s\n", command.Code)
+	return true
+}
+
+// copyCode puts the code of a command, with its variables resolved, into the clipboard. If there's no
+// clipboard available, the code is printed instead
+func (ctrl *CLIController) copyCode(command *models.Command) {
+	code, err := ctrl.resolveCode(command)
+	if err != nil {
+		log.Fatalf("copy code: '%s': %v", command.Selector.String(), err)
+	}
+
+	provider, err := tty.Copy(code)
+	if err != nil {
+		console.PrintWarning(fmt.Sprintf("Could not copy to the clipboard (%v), printing it instead", err))
+		tty.Print("%s\n", code)
+		return
+	}
+	console.PrintSuccess(fmt.Sprintf("Code of '%s' copied to the clipboard (%s)", command.Selector.String(), provider))
+}
+
+// handleListingAction performs the action requested by the user over the command picked in an interactive listing
+func (ctrl *CLIController) handleListingAction(command *models.Command, action string) {
+	if command == nil {
+		return
+	}
+
+	switch action {
+	case console.ActionRun:
+		if exitCode := ctrl.runCommand(command); exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	case console.ActionCopy:
+		ctrl.copyCode(command)
+	case console.ActionEdit:
+		ctrl.CommandEdit(command.ID)
+	case console.ActionDelete:
+		ctrl.CommandDelete(command.ID)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"log"
 	"strings"
 
+	"github.com/dplabs/cbox/src/core"
 	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/repository"
 	"github.com/dplabs/cbox/src/tools/console"
 )
 
@@ -15,10 +17,15 @@ func (ctrl *CLIController) SearchCommands(spcSelectorStr *string, criteria strin
 		log.Fatal("search: criteria not specified")
 	}
 
-	if strings.Contains(criteria, "@") {
+	if !RegexFlag && !strings.ContainsAny(criteria, ": ") && strings.Contains(criteria, "@") {
 		log.Fatalf("search: criteria not specified - this looks like a selector")
 	}
 
+	matcher, err := searchMatcher(criteria)
+	if err != nil {
+		log.Fatalf("search: %v", err)
+	}
+
 	sel := ""
 	if spcSelectorStr != nil {
 		sel = *spcSelectorStr
@@ -29,30 +36,192 @@ func (ctrl *CLIController) SearchCommands(spcSelectorStr *string, criteria strin
 		log.Fatalf("search: %v", err)
 	}
 
-	var spaces []*models.Space = []*models.Space{}
-	if sel != "" {
-		space, err := ctrl.findSpace(selector)
+	scope, err := newSearchScope(selector, sel != "")
+	if err != nil {
+		log.Fatalf("search: %v", err)
+	}
+
+	var commands []*models.Command
+	if searcher := core.Searcher(); searcher != nil {
+		commands = ctrl.searchCommandsInStore(searcher, scope, matcher)
+	} else {
+		commands = ctrl.searchCommandsInSpaces(scope, matcher)
+	}
+	results := models.GroupBySpace(models.Rank(matcher, commands))
+
+	remote := map[*models.Command]bool{}
+	if RemoteFlag {
+		for _, group := range ctrl.searchCommandsInCloudCache(scope, matcher) {
+			results = append(results, group)
+			for _, command := range group.Commands {
+				remote[command] = true
+			}
+		}
+	}
+
+	header := fmt.Sprintf("Results for \"%s\"", criteria)
+	if RegexFlag {
+		header = fmt.Sprintf("Results for /%s/", criteria)
+	}
+	if spcSelectorStr != nil {
+		header = fmt.Sprintf("%s in '%s'", header, selector.String())
+	}
+	console.HighlightMatches(matcher)
+	command, action := console.PrintSearchResults(header, results, ListingsModeOption, ListingsSortOption)
+
+	if remote[command] && (action == console.ActionEdit || action == console.ActionDelete) {
+		log.Fatalf("search: '%s' is a cached cloud command, copy it first with 'cbox cloud copy'", command.ID)
+	}
+	ctrl.handleListingAction(command, action)
+}
+
+// searchScope are the spaces a search looks into: the ones given or, if none, every space (optionally
+// only those within a namespace)
+type searchScope struct {
+	tag           string
+	spaces        []*models.Selector
+	namespaceType int
+	namespace     string
+}
+
+func newSearchScope(selector *models.Selector, spaceSpecified bool) (*searchScope, error) {
+	scope := searchScope{tag: selector.Item, spaces: []*models.Selector{}}
+
+	if spaceSpecified && selector.Space != "" {
+		scope.spaces = append(scope.spaces, selector.CloneForItem(""))
+	}
+	for _, name := range SearchSpacesOption {
+		if !strings.HasPrefix(name, "@") {
+			name = "@" + name
+		}
+		space, err := models.ParseSelector(name)
 		if err != nil {
-			log.Fatalf("search: %v", err)
+			return nil, err
+		}
+		scope.spaces = append(scope.spaces, space)
+	}
+
+	if NamespaceOption != "" {
+		if len(scope.spaces) != 0 {
+			return nil, fmt.Errorf("a namespace can't be given along with specific spaces")
+		}
+		var err error
+		if scope.namespaceType, scope.namespace, err = models.ParseNamespaceFilter(NamespaceOption); err != nil {
+			return nil, err
+		}
+	}
+
+	if SearchAllFlag && (len(scope.spaces) != 0 || scope.namespace != "") {
+		return nil, fmt.Errorf("all spaces can't be searched if some spaces or a namespace are given")
+	}
+
+	return &scope, nil
+}
+
+// includes checks if a space not explicitly given is within the scope
+func (scope *searchScope) includes(space *models.Selector) bool {
+	return scope.namespace == "" || space.InNamespace(scope.namespaceType, scope.namespace)
+}
+
+// includesRemote checks if a cloud space is within the scope: spaces given without namespace match
+// any cloud space with the same label
+func (scope *searchScope) includesRemote(space *models.Selector) bool {
+	if len(scope.spaces) == 0 {
+		return scope.includes(space)
+	}
+	for _, s := range scope.spaces {
+		if s.Space == space.Space && (s.NamespaceType == models.TypeNone || s.NamespaceType == space.NamespaceType && s.Namespace == space.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchMatcher builds the matcher for a search: a regular expression if requested, a query otherwise
+func searchMatcher(criteria string) (models.Matcher, error) {
+	if RegexFlag {
+		return models.NewRegexMatcher(criteria, SearchFieldsOption, CaseOption)
+	}
+
+	if len(SearchFieldsOption) != 0 || CaseOption != "" {
+		return nil, fmt.Errorf("fields and case mode can only be set for regular expressions (use 'field:term' in queries)")
+	}
+	return models.ParseQuery(criteria)
+}
+
+func (ctrl *CLIController) searchCommandsInSpaces(scope *searchScope, matcher models.Matcher) []*models.Command {
+	var spaces []*models.Space = []*models.Space{}
+	if len(scope.spaces) != 0 {
+		for _, selector := range scope.spaces {
+			space, err := ctrl.findSpace(selector)
+			if err != nil {
+				log.Fatalf("search: %v", err)
+			}
+			spaces = append(spaces, space)
 		}
-		spaces = append(spaces, space)
 	} else {
-		spaces = ctrl.cbox.Spaces
+		for _, space := range ctrl.box().Spaces {
+			if scope.includes(space.Selector) {
+				spaces = append(spaces, space)
+			}
+		}
 	}
 
 	var commands []*models.Command = []*models.Command{}
 	for _, space := range spaces {
-		var err error
-		cs, err := space.SearchCommands(selector.Item, criteria)
+		commands = append(commands, space.Search(scope.tag, matcher)...)
+	}
+	return commands
+}
+
+// searchCommandsInStore delegates the search to the storage backend, so spaces don't need to be loaded
+func (ctrl *CLIController) searchCommandsInStore(searcher repository.Searcher, scope *searchScope, matcher models.Matcher) []*models.Command {
+	if len(scope.spaces) == 0 {
+		found, err := searcher.SearchCommands(nil, scope.tag, matcher)
+		if err != nil {
+			log.Fatalf("search: %v", err)
+		}
+
+		commands := []*models.Command{}
+		for _, command := range found {
+			if scope.includes(command.Selector) {
+				commands = append(commands, command)
+			}
+		}
+		return commands
+	}
+
+	commands := []*models.Command{}
+	for _, selector := range scope.spaces {
+		space, err := ctrl.resolveSpace(selector, searcher.SpaceFind)
+		if err != nil {
+			log.Fatalf("search: %v", err)
+		}
+
+		found, err := searcher.SearchCommands(space.Selector, scope.tag, matcher)
 		if err != nil {
 			log.Fatalf("search: %v", err)
 		}
-		commands = append(commands, cs...)
+		commands = append(commands, found...)
 	}
+	return commands
+}
 
-	header := fmt.Sprintf("Results for \"%s\"", criteria)
-	if spcSelectorStr != nil {
-		header = fmt.Sprintf("%s in '%s'", header, selector.String())
+// searchCommandsInCloudCache looks for commands in the cloud spaces fetched before
+func (ctrl *CLIController) searchCommandsInCloudCache(scope *searchScope, matcher models.Matcher) []*models.SearchResults {
+	cached, err := core.CloudCacheList()
+	if err != nil {
+		log.Fatalf("search: %v", err)
+	}
+
+	results := []*models.SearchResults{}
+	for _, space := range cached {
+		if !scope.includesRemote(space.Selector) {
+			continue
+		}
+		if found := space.Search(scope.tag, matcher); len(found) != 0 {
+			results = append(results, &models.SearchResults{Space: space.Selector, Remote: true, Commands: found})
+		}
 	}
-	console.PrintCommandList(header, commands, ListingsModeOption, ListingsSortOption)
+	return results
 }
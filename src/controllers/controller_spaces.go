@@ -12,9 +12,7 @@ import (
 )
 
 func (ctrl *CLIController) SpacesList() {
-	for _, space := range ctrl.cbox.Spaces {
-		console.PrintSpace("", space)
-	}
+	console.PrintSpaces(ctrl.box().Spaces)
 }
 
 func (ctrl *CLIController) SpacesCreate() {
@@ -22,15 +20,15 @@ func (ctrl *CLIController) SpacesCreate() {
 
 	space := console.ReadSpace()
 
-	err := ctrl.cbox.SpaceCreate(space)
+	err := ctrl.box().SpaceCreate(space)
 	for err != nil {
 		console.PrintError("Space already found in your cbox. Try a different one")
 		space.Label = strings.ToLower(console.ReadString("Label", console.NOT_EMPTY_VALUES, console.ONLY_VALID_CHARS))
 		space.Selector.Space = space.Label
-		err = ctrl.cbox.SpaceCreate(space)
+		err = ctrl.box().SpaceCreate(space)
 	}
 
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
 
 	console.PrintSpace("New space", space)
 
@@ -52,22 +50,37 @@ func (ctrl *CLIController) SpacesEdit(spcSelectorStr string) {
 
 	console.PrintSpace("Space to edit", space)
 
-	console.EditSpace(space)
+	if EditorFlag {
+		err := console.EditSpaceInEditor(space, func(edited *models.Space) error {
+			if edited.Label == selector.Space {
+				return nil
+			}
+			if _, err := ctrl.box().SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, edited.Label); err == nil {
+				return fmt.Errorf("label '%s' already found in your cbox", edited.Label)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("edit space: %v", err)
+		}
+	} else {
+		console.EditSpace(space)
+	}
 	space.Selector.Space = space.Label
 
-	err = ctrl.cbox.SpaceEdit(space, selector.Namespace, selector.Space)
+	err = ctrl.box().SpaceEdit(space, selector.Namespace, selector.Space)
 	for err != nil {
 		console.PrintError(fmt.Sprintf("Label '%s' already found in space. Try a different one", space.Label))
 		space.Label = strings.ToLower(console.ReadString("Label", console.NOT_EMPTY_VALUES, console.ONLY_VALID_CHARS))
 		space.Selector.Space = space.Label
-		err = ctrl.cbox.SpaceEdit(space, selector.Namespace, selector.Space)
+		err = ctrl.box().SpaceEdit(space, selector.Namespace, selector.Space)
 	}
 
 	console.PrintSpace("Space after edition", space)
 
 	if tty.Confirm("Update?") {
 		ctrl.cleanOldSpaceFile(space, selector)
-		core.Save(ctrl.cbox)
+		core.Save(ctrl.box())
 		console.PrintSuccess("Space updated successfully!")
 	} else {
 		console.PrintError("Edition cancelled")
@@ -90,13 +103,14 @@ func (ctrl *CLIController) SpacesDestroy(spcSelectorStr string) {
 	console.PrintSpace("Space to destroy", space)
 
 	if tty.Confirm("Are you sure you want to destroy this space?") {
-		err = ctrl.cbox.SpaceDestroy(space)
+		core.Trash(models.NewSpaceTrashEntry(space))
+		err = ctrl.box().SpaceDestroy(space)
 		if err != nil {
 			log.Fatalf("destroy space: %v", err)
 		}
 		core.DeleteSpaceFile(space.Selector)
 
-		console.PrintSuccess("Space destroyed successfully!")
+		console.PrintSuccess(fmt.Sprintf("Space destroyed successfully! (restore it with 'cbox trash restore %s')", space.Selector.String()))
 	} else {
 		console.PrintError("Deletion cancelled")
 	}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/dplabs/cbox/src/core"
+	"github.com/dplabs/cbox/src/repository"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/spf13/viper"
+)
+
+func (ctrl *CLIController) StorageMigrate() {
+	console.PrintAction("Migrating storage backend")
+
+	if StorageBackendOption == "" {
+		log.Fatalf("storage migrate: target backend not specified")
+	}
+
+	valid := false
+	for _, backend := range repository.StorageBackends() {
+		valid = valid || backend == StorageBackendOption
+	}
+	if !valid {
+		log.Fatalf("storage migrate: unknown backend '%s' (available: %v)", StorageBackendOption, repository.StorageBackends())
+	}
+
+	tty.Print("Migrating spaces from '%s' into '%s'...\n\n", core.StorageBackend(), StorageBackendOption)
+
+	if tty.Confirm("Continue?") {
+		count, err := core.StorageMigrate(StorageBackendOption)
+		if err != nil {
+			log.Fatalf("storage migrate: %v", err)
+		}
+
+		viper.Set("cbox.storage.backend", StorageBackendOption)
+
+		console.PrintSuccess(fmt.Sprintf("%d spaces migrated successfully!", count))
+	} else {
+		console.PrintError("Migration cancelled")
+	}
+}
+
+func (ctrl *CLIController) StorageCheck() {
+	console.PrintAction("Checking storage")
+
+	tty.Print("Checking spaces stored using backend '%s'...\n\n", core.StorageBackend())
+
+	problems := core.StorageCheck()
+	for _, problem := range problems {
+		console.PrintError(problem.Error())
+	}
+
+	if len(problems) != 0 {
+		log.Fatalf("storage check: %d problems found", len(problems))
+	}
+
+	console.PrintSuccess("No problems found!")
+}
@@ -23,17 +23,26 @@ func (ctrl *CLIController) TagsList(spcSelectorStr *string) {
 		log.Fatalf("list tags: %v", err)
 	}
 
-	space, err := ctrl.findSpace(selector)
-	if err != nil {
-		log.Fatalf("list tags: %v", err)
+	var tags []string
+	if searcher := core.Searcher(); searcher != nil {
+		space, err := ctrl.resolveSpace(selector, searcher.SpaceFind)
+		if err != nil {
+			log.Fatalf("list tags: %v", err)
+		}
+		tags, err = searcher.TagsList(space.Selector, selector.Item)
+		if err != nil {
+			log.Fatalf("list tags: %v", err)
+		}
+	} else {
+		space, err := ctrl.findSpace(selector)
+		if err != nil {
+			log.Fatalf("list tags: %v", err)
+		}
+		tags = space.TagsList(selector.Item)
 	}
-
-	tags := space.TagsList(selector.Item)
 	sort.Strings(tags)
 
-	for _, tag := range tags {
-		console.PrintTag(tag)
-	}
+	console.PrintTags(tags)
 }
 
 func (ctrl *CLIController) TagsAdd(cmdSelectorStr string, tags ...string) {
@@ -44,10 +53,11 @@ func (ctrl *CLIController) TagsAdd(cmdSelectorStr string, tags ...string) {
 		log.Fatalf("add tags: %v", err)
 	}
 
-	_, command, err := ctrl.findSpaceAndCommand(selector)
+	space, command, err := ctrl.findSpaceAndCommand(selector)
 	if err != nil {
 		log.Fatalf("add tags: %v", err)
 	}
+	previous := snapshot(command)
 
 	tty.Print("Adding tags to command with label '%s'...\n", command.Label)
 
@@ -59,8 +69,11 @@ func (ctrl *CLIController) TagsAdd(cmdSelectorStr string, tags ...string) {
 			command.TagAdd(tag)
 		}
 	}
+	if err := space.CommandEdit(command, previous, ctrl.author()); err != nil {
+		log.Fatalf("add tags: %v", err)
+	}
 
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
 
 	console.PrintCommand("Tagged command", command, false)
 
@@ -75,10 +88,11 @@ func (ctrl *CLIController) TagsRemove(cmdSelectorStr string, tags ...string) {
 		log.Fatalf("remove tags: %v", err)
 	}
 
-	_, command, err := ctrl.findSpaceAndCommand(selector)
+	space, command, err := ctrl.findSpaceAndCommand(selector)
 	if err != nil {
 		log.Fatalf("remove tags: %v", err)
 	}
+	previous := snapshot(command)
 
 	tty.Print("Removing tags from command with label '%s'...\n", command.Label)
 
@@ -87,8 +101,11 @@ func (ctrl *CLIController) TagsRemove(cmdSelectorStr string, tags ...string) {
 			command.TagDelete(tag)
 		}
 	}
+	if err := space.CommandEdit(command, previous, ctrl.author()); err != nil {
+		log.Fatalf("remove tags: %v", err)
+	}
 
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
 
 	console.PrintCommand("Untagged command", command, false)
 
@@ -116,12 +133,16 @@ func (ctrl *CLIController) TagsDelete(tagSelectorStr string) {
 		if err != nil {
 			log.Fatalf("delete tags: %v", err)
 		}
+		previous := snapshot(command)
 		command.TagDelete(selector.Item)
+		if err := space.CommandEdit(command, previous, ctrl.author()); err != nil {
+			log.Fatalf("delete tags: %v", err)
+		}
 
 		console.PrintCommand("Untagged command", command, false)
 	}
 
-	core.Save(ctrl.cbox)
+	core.Save(ctrl.box())
 
 	console.PrintSuccess(fmt.Sprintf("\nTag '%s' successfully deleted from space '%s'!", selector.Item, selector.Space))
 }
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dplabs/cbox/src/core"
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+func (ctrl *CLIController) TrashList() {
+	entries, err := core.TrashList()
+	if err != nil {
+		log.Fatalf("list trash: %v", err)
+	}
+
+	console.PrintTrashEntries(entries)
+}
+
+func (ctrl *CLIController) TrashRestore(selectorStr string) {
+	console.PrintAction("Restoring from trash")
+
+	selector, err := models.ParseSelector(selectorStr)
+	if err != nil {
+		log.Fatalf("restore: %v", err)
+	}
+
+	entry, err := ctrl.findTrashEntry(selector)
+	if err != nil {
+		log.Fatalf("restore: %v", err)
+	}
+
+	if entry.Command != nil {
+		space, err := ctrl.findSpace(entry.Command.Selector)
+		if err != nil {
+			log.Fatalf("restore: space '%s' not found, restore it first", entry.Command.Selector.CloneForItem("").String())
+		}
+
+		// the command overwritten is deleted too, so it goes to the trash as well
+		existing, err := space.CommandFind(entry.Command.Label)
+		if err == nil && ForceFlag {
+			core.Trash(models.NewCommandTrashEntry(existing))
+		}
+
+		err = space.CommandAdd(entry.Command, ForceFlag)
+		if err != nil {
+			log.Fatalf("restore: %v (use --force to overwrite it)", err)
+		}
+
+		console.PrintCommand("Restored command", entry.Command, false)
+	} else {
+		err = ctrl.box().SpaceCreate(entry.Space)
+		if err != nil {
+			log.Fatalf("restore: %v", err)
+		}
+
+		console.PrintSpace("Restored space", entry.Space)
+	}
+
+	core.Save(ctrl.box())
+
+	if err := core.TrashRemove(entry); err != nil {
+		log.Fatalf("restore: %v", err)
+	}
+
+	console.PrintSuccess("Restored successfully!")
+}
+
+// findTrashEntry returns the most recently deleted content matching a selector. As when finding spaces,
+// selectors without namespace may refer to the content of the logged in user
+func (ctrl *CLIController) findTrashEntry(selector *models.Selector) (*models.TrashEntry, error) {
+	entries, err := core.TrashList()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*models.Selector{selector}
+	if selector.NamespaceType == models.TypeNone && ctrl.cloud != nil && ctrl.cloud.Login != "" {
+		candidates = append(candidates, models.NewSelector(models.TypeUser, ctrl.cloud.Login, selector.Space, selector.Item))
+	}
+
+	for _, candidate := range candidates {
+		for _, entry := range entries {
+			if entry.Matches(candidate) {
+				return entry, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("'%s' not found in trash", selector.String())
+}
+
+func (ctrl *CLIController) TrashEmpty() {
+	console.PrintAction("Emptying trash")
+
+	var olderThan time.Duration
+	if OlderThanOption != "" {
+		var err error
+		olderThan, err = tools.ParseDuration(OlderThanOption)
+		if err != nil {
+			log.Fatalf("empty trash: %v", err)
+		}
+	}
+
+	entries, err := core.TrashList()
+	if err != nil {
+		log.Fatalf("empty trash: %v", err)
+	}
+
+	limit := time.Now().Add(-olderThan)
+
+	toRemove := []*models.TrashEntry{}
+	for _, entry := range entries {
+		if !time.Time(entry.DeletedAt).After(limit) {
+			toRemove = append(toRemove, entry)
+			console.PrintTrashEntry(entry)
+		}
+	}
+
+	if len(toRemove) == 0 {
+		tty.Print("Nothing to delete\n")
+		return
+	}
+
+	if tty.Confirm(fmt.Sprintf("Are you sure you want to permanently delete %d entries?", len(toRemove))) {
+		for _, entry := range toRemove {
+			if err := core.TrashRemove(entry); err != nil {
+				log.Fatalf("empty trash: %v", err)
+			}
+		}
+		console.PrintSuccess("Trash emptied successfully!")
+	} else {
+		console.PrintError("Deletion cancelled")
+	}
+}
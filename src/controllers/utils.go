@@ -2,18 +2,27 @@ package controllers
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/dplabs/cbox/src/core"
 	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/mitchellh/copystructure"
 )
 
+type spaceFinder func(namespaceType int, namespace string, label string) (*models.Space, error)
+
 func (ctrl *CLIController) findSpace(selector *models.Selector) (*models.Space, error) {
+	return ctrl.resolveSpace(selector, ctrl.box().SpaceFind)
+}
+
+func (ctrl *CLIController) resolveSpace(selector *models.Selector, find spaceFinder) (*models.Space, error) {
 
 	if selector == nil {
 		return nil, fmt.Errorf("find space: nil selector")
 	}
 
-	space, err := ctrl.cbox.SpaceFind(selector.NamespaceType, selector.Namespace, selector.Space)
+	space, err := find(selector.NamespaceType, selector.Namespace, selector.Space)
 
 	// if not namespace specified, maybe belongs to the logged in user
 	if selector.NamespaceType != models.TypeNone || err == nil {
@@ -21,7 +30,7 @@ func (ctrl *CLIController) findSpace(selector *models.Selector) (*models.Space,
 	}
 
 	if ctrl.cloud != nil {
-		return ctrl.cbox.SpaceFind(models.TypeUser, ctrl.cloud.Login, selector.Space)
+		return find(models.TypeUser, ctrl.cloud.Login, selector.Space)
 	} else {
 		return nil, err
 	}
@@ -50,3 +59,32 @@ func (ctrl *CLIController) cleanOldSpaceFile(space *models.Space, oldSelector *m
 		core.DeleteSpaceFile(oldSelector)
 	}
 }
+
+// resolveCode replaces the variables of a command with the values given with --var, asking for the missing ones
+func (ctrl *CLIController) resolveCode(command *models.Command) (string, error) {
+	values, err := models.ParseVariableValues(VariablesOption)
+	if err != nil {
+		return "", err
+	}
+
+	values = console.ReadVariables(command.Variables(), values)
+
+	return command.ResolveCode(values)
+}
+
+// author returns who is editing commands: the user logged in the cloud, if any
+func (ctrl *CLIController) author() string {
+	if ctrl.cloud == nil {
+		return ""
+	}
+	return ctrl.cloud.Login
+}
+
+// snapshot copies a command before editing it, so the edition can be recorded as a new revision
+func snapshot(command *models.Command) models.Command {
+	copy, err := copystructure.Copy(*command)
+	if err != nil {
+		log.Fatalf("copy command: %v", err)
+	}
+	return copy.(models.Command)
+}
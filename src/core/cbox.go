@@ -22,17 +22,21 @@ var (
 	repo              *repository.Repository
 )
 
-func Load(path string) *models.CBox {
+// Init prepares the repository and returns a cbox without any space loaded yet
+func Init(path string) *models.CBox {
 
 	repo = repository.InitRepository(path)
 
-	spaces, isNewRepository := repo.LoadSpaces()
-
-	cbox := &models.CBox{
+	return &models.CBox{
 		Spaces:  []*models.Space{},
 		Version: Version,
 		Build:   Build,
 	}
+}
+
+func LoadSpaces(cbox *models.CBox) {
+
+	spaces, isNewRepository := repo.LoadSpaces()
 
 	if isNewRepository {
 		createDefaultSpace(cbox)
@@ -45,6 +49,11 @@ func Load(path string) *models.CBox {
 			log.Fatalf("load: could not create space: %v", err)
 		}
 	}
+}
+
+func Load(path string) *models.CBox {
+	cbox := Init(path)
+	LoadSpaces(cbox)
 	return cbox
 }
 
@@ -64,11 +73,60 @@ func createDefaultSpace(cbox *models.CBox) {
 }
 
 func Save(cbox *models.CBox) {
-	for _, space := range cbox.Spaces {
-		repo.Persist(space)
-	}
+	repo.Save(cbox.Spaces)
 }
 
 func DeleteSpaceFile(selector *models.Selector) {
 	repo.Delete(selector)
 }
+
+func Trash(entry *models.TrashEntry) {
+	if err := repo.Trash(entry); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
+
+func TrashList() ([]*models.TrashEntry, error) {
+	return repo.TrashList()
+}
+
+func TrashRemove(entry *models.TrashEntry) error {
+	return repo.TrashRemove(entry)
+}
+
+func CloudCacheStore(space *models.Space) error {
+	return repo.CloudCacheStore(space)
+}
+
+func CloudCacheList() ([]*repository.CachedSpace, error) {
+	return repo.CloudCacheList()
+}
+
+func SyncStateFind(selector *models.Selector) (*models.SyncState, error) {
+	return repo.SyncStateFind(selector)
+}
+
+func SyncStateStore(state *models.SyncState) error {
+	return repo.SyncStateStore(state)
+}
+
+// Searcher returns the current storage backend if it's able to search without loading every space, nil otherwise
+func Searcher() repository.Searcher {
+	return repo.Searcher()
+}
+
+func SaveSettings() error {
+	return repo.SaveSettings()
+}
+
+func StorageBackend() string {
+	return repo.GetStorageBackend()
+}
+
+func StorageCheck() []error {
+	return repo.Check()
+}
+
+func StorageMigrate(backend string) (int, error) {
+	return repo.Migrate(backend)
+}
@@ -1,11 +1,13 @@
 package core
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 
 	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
 )
 
 func CloudClient(cbox *models.CBox) *models.Cloud {
@@ -17,6 +19,13 @@ func CloudClient(cbox *models.CBox) *models.Cloud {
 		log.Fatalf("cloud: could not parse server's URL: %v", err)
 	}
 
+	httpClient, err := tools.NewHTTPClient(repo.CloudHTTPOptions())
+	if err != nil {
+		// only requests to the cloud fail, so the settings can still be fixed
+		httpClient = &http.Client{Transport: failingTransport{fmt.Errorf("cloud: invalid settings: %v", err)}}
+	}
+	retries, retryWait := repo.CloudRetries()
+
 	cloud := models.Cloud{
 		Environment: repo.GetEnv(),
 		ServerKey:   serverKey,
@@ -26,13 +35,26 @@ func CloudClient(cbox *models.CBox) *models.Cloud {
 		Token:       token,
 		URL:         serverURL,
 		BaseURL:     baseUrl,
-		HttpClient:  http.DefaultClient,
+		HttpClient:  httpClient,
 		Cbox:        cbox,
+		Cache:       repo,
+		CacheTTL:    repo.CloudCacheTTL(),
+		Retries:     retries,
+		RetryWait:   retryWait,
 	}
 
 	return &cloud
 }
 
+// failingTransport fails every request, with the error found setting up the connection to the cloud
+type failingTransport struct {
+	err error
+}
+
+func (transport failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, transport.err
+}
+
 func StoreCloudSettings(cloud *models.Cloud) {
 	repo.StoreCloudSettings(cloud)
 }
@@ -3,16 +3,35 @@ package models
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"os"
+	"time"
 
 	"github.com/dplabs/cbox/src/tools"
 	"github.com/dplabs/cbox/src/tools/tty"
 )
 
+const (
+	cloudKindSpaces   = "spaces"
+	cloudKindCommands = "commands"
+
+	cloudMaxRetryWait = 30 * time.Second
+)
+
+var (
+	// ErrCloudNotFound is returned (wrapped) when the cloud doesn't know about what was requested
+	ErrCloudNotFound = errors.New("not found in the cloud")
+	// ErrCloudOffline is returned (wrapped) when something can't be done without reaching the cloud
+	ErrCloudOffline = errors.New("working offline")
+)
+
 func (cloud *Cloud) ServerLogin(jwt string) (string, error) {
 	userID, login, name, err := tools.VerifyJWT(jwt, cloud.ServerKey)
 
@@ -29,23 +48,69 @@ func (cloud *Cloud) ServerLogin(jwt string) (string, error) {
 }
 
 func (cloud *Cloud) doRequest(method string, path string, query map[string]string, body string) (string, error) {
+	status, _, response, err := cloud.send(method, path, query, body, nil)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", cloud.responseError(status, response)
+	}
+	return response, nil
+}
+
+// send performs a request to the cloud, returning the status, headers and body of its response. Requests
+// failing or answered with a server error are retried (if idempotent) with an exponential backoff, as are
+// the ones rejected for exceeding the rate limit
+func (cloud *Cloud) send(method string, path string, query map[string]string, body string, headers map[string]string) (int, http.Header, string, error) {
+	if cloud.Offline {
+		return 0, nil, "", fmt.Errorf("rest: %w", ErrCloudOffline)
+	}
+
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+
+	for attempt := 0; ; attempt++ {
+		status, header, response, err := cloud.sendOnce(method, path, query, body, headers)
+
+		retry := status == http.StatusTooManyRequests || idempotent && (transientError(err) || status >= http.StatusInternalServerError)
+		if !retry || attempt >= cloud.Retries {
+			return status, header, response, err
+		}
+
+		retryAfter := ""
+		if header != nil {
+			retryAfter = header.Get("Retry-After")
+		}
+		wait := tools.RetryWait(attempt, cloud.RetryWait, cloudMaxRetryWait, retryAfter)
+		if cloud.Environment == "test" {
+			tty.Debug(fmt.Sprintf("retrying in %v (attempt %d failed: %d %v)", wait, attempt+1, status, err))
+		}
+		time.Sleep(wait)
+	}
+}
+
+// transientError tells if a request failed for reasons that may go away when retried: connection problems
+// or timeouts
+func transientError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) || os.IsTimeout(err) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (cloud *Cloud) sendOnce(method string, path string, query map[string]string, body string, headers map[string]string) (int, http.Header, string, error) {
 	rel := &url.URL{Path: path}
 	url := cloud.BaseURL.ResolveReference(rel)
 
 	var jsonStr = []byte(body)
 
-	version := cloud.Cbox.Version
-	if version == "development" {
-		version = "0.0.0"
-	}
-
 	req, err := http.NewRequest(method, url.String(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		return "", err
+		return 0, nil, "", err
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Authorization", "Bearer "+cloud.Token)
-	req.Header.Set("cbox-version", version)
+	req.Header.Set("cbox-version", cloud.version())
+	for header, value := range headers {
+		req.Header.Set(header, value)
+	}
 
 	if len(query) != 0 {
 		q := req.URL.Query()
@@ -62,13 +127,13 @@ func (cloud *Cloud) doRequest(method string, path string, query map[string]strin
 
 	resp, err := cloud.HttpClient.Do(req)
 	if err != nil {
-		return "", err
+		return 0, nil, "", err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("rest: could not read response body: %v", err)
+		return 0, nil, "", fmt.Errorf("rest: could not read response body: %w", err)
 	}
 	bodyString := string(bodyBytes)
 
@@ -76,13 +141,92 @@ func (cloud *Cloud) doRequest(method string, path string, query map[string]strin
 		tty.Debug(fmt.Sprintf("%s\n\n---\n", bodyString))
 	}
 
-	if resp.StatusCode == http.StatusOK {
-		return bodyString, nil
-	} else if resp.StatusCode == http.StatusNotAcceptable {
-		return "", fmt.Errorf("rest: client version not supported by server: %s\n%s", req.Header.Get("cbox-version"), tty.ColorRed(bodyString))
+	return resp.StatusCode, resp.Header, bodyString, nil
+}
+
+func (cloud *Cloud) responseError(status int, body string) error {
+	if status == http.StatusNotFound {
+		return fmt.Errorf("rest: %w: %s", ErrCloudNotFound, tty.ColorRed(body))
+	} else if status == http.StatusNotAcceptable {
+		return fmt.Errorf("rest: client version not supported by server: %s\n%s", cloud.version(), tty.ColorRed(body))
+	}
+
+	return fmt.Errorf("rest: request failed with '%d %s' (code: %d):\n%s", status, http.StatusText(status), status, tty.ColorRed(body))
+}
+
+func (cloud *Cloud) version() string {
+	if cloud.Cbox.Version == "development" {
+		return "0.0.0"
+	}
+	return cloud.Cbox.Version
+}
+
+// cachedGet retrieves something about a selector from the cloud, reusing the response cached for it
+// while fresh and revalidating it (with its ETag and Last-Modified date) once stale. When offline, the
+// cached response is used no matter how old it is
+func (cloud *Cloud) cachedGet(kind string, selector *Selector) (string, error) {
+	path := "/v1/" + kind
+	query := map[string]string{"selector": selector.String()}
+	if cloud.Cache == nil {
+		return cloud.doRequest("GET", path, query, "")
+	}
+
+	cached, err := cloud.Cache.CloudResponseFind(kind, selector)
+	if err != nil {
+		return "", err
+	}
+
+	if cloud.Offline {
+		if cached == nil {
+			return "", fmt.Errorf("rest: %w: '%s' not cached yet", ErrCloudOffline, selector.String())
+		}
+		return cached.Body, nil
+	}
+	if cloud.fresh(cached) {
+		return cached.Body, nil
+	}
+
+	headers := map[string]string{}
+	if cached != nil && cached.ETag != "" {
+		headers["If-None-Match"] = cached.ETag
+	}
+	if cached != nil && cached.LastModified != "" {
+		headers["If-Modified-Since"] = cached.LastModified
+	}
+
+	status, header, body, err := cloud.send("GET", path, query, "", headers)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case status == http.StatusNotModified && cached != nil:
+		cached.FetchedAt = UnixTimeNow()
+	case status == http.StatusOK:
+		cached = &CloudResponse{Body: body, ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified"), FetchedAt: UnixTimeNow()}
+	case status == http.StatusNotFound:
+		// forget whatever was cached: the space is gone (errors are ignored, as it's just a cache)
+		cloud.Cache.CloudResponsesDelete(selector)
+		return "", cloud.responseError(status, body)
+	default:
+		return "", cloud.responseError(status, body)
 	}
 
-	return "", fmt.Errorf("rest: request failed with '%s' (code: %d):\n%s", resp.Status, resp.StatusCode, tty.ColorRed(bodyString))
+	// a cache that can't be written is not a reason to fail
+	cloud.Cache.CloudResponseStore(kind, selector, cached)
+	return cached.Body, nil
+}
+
+// fresh tells if a cached response can still be used without revalidating it
+func (cloud *Cloud) fresh(cached *CloudResponse) bool {
+	return cached != nil && time.Since(time.Time(cached.FetchedAt)) < cloud.CacheTTL
+}
+
+// forget drops the responses cached for a space, once changed
+func (cloud *Cloud) forget(selector *Selector) {
+	if cloud.Cache != nil {
+		cloud.Cache.CloudResponsesDelete(selector)
+	}
 }
 
 func (cloud *Cloud) SpacePublish(space *Space) error {
@@ -93,6 +237,9 @@ func (cloud *Cloud) SpacePublish(space *Space) error {
 	}
 
 	_, err = cloud.doRequest("POST", "/v1/spaces", nil, string(jsonSpace))
+	if err == nil {
+		cloud.forget(space.Selector)
+	}
 
 	return err
 }
@@ -104,6 +251,9 @@ func (cloud *Cloud) SpaceUnpublish(selector *Selector) error {
 	query["selector"] = selector.String()
 
 	_, err := cloud.doRequest("DELETE", "/v1/spaces", query, "")
+	if err == nil {
+		cloud.forget(selector)
+	}
 
 	return err
 }
@@ -111,11 +261,7 @@ func (cloud *Cloud) SpaceUnpublish(selector *Selector) error {
 // This method retrieves details about an space from the cloud, but not its entries
 func (cloud *Cloud) SpaceFind(selector *Selector) (*Space, error) {
 
-	query := make(map[string]string)
-
-	query["selector"] = selector.String()
-
-	response, err := cloud.doRequest("GET", "/v1/spaces", query, "")
+	response, err := cloud.cachedGet(cloudKindSpaces, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -133,16 +279,30 @@ func (cloud *Cloud) SpaceFind(selector *Selector) (*Space, error) {
 
 func (cloud *Cloud) CommandList(selector *Selector) ([]*Command, error) {
 
-	query := make(map[string]string)
-	query["selector"] = selector.String()
+	// commands of a space already listed (like the ones previewed while picking one of them) are found
+	// in the listing cached, while fresh
+	if selector.Item != "" && cloud.Cache != nil {
+		cached, err := cloud.Cache.CloudResponseFind(cloudKindCommands, selector.CloneForItem(""))
+		if err == nil && cached != nil && (cloud.Offline || cloud.fresh(cached)) {
+			commands, err := parseCommands(cached.Body)
+			if err != nil {
+				return nil, err
+			}
+			return (&Space{Entries: commands}).CommandList(selector.Item), nil
+		}
+	}
 
-	response, err := cloud.doRequest("GET", "/v1/commands", query, "")
+	response, err := cloud.cachedGet(cloudKindCommands, selector)
 	if err != nil {
 		return nil, err
 	}
 
+	return parseCommands(response)
+}
+
+func parseCommands(response string) ([]*Command, error) {
 	var commands []*Command
-	err = json.Unmarshal([]byte(response), &commands)
+	err := json.Unmarshal([]byte(response), &commands)
 	if err != nil {
 		return nil, fmt.Errorf("cloud: list commands: could not parse response: %v", err)
 	}
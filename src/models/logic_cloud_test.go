@@ -0,0 +1,184 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+)
+
+// memoryCache keeps the responses of the cloud in memory
+type memoryCache map[string]*models.CloudResponse
+
+func (cache memoryCache) CloudResponseFind(kind string, selector *models.Selector) (*models.CloudResponse, error) {
+	return cache[kind+" "+selector.String()], nil
+}
+
+func (cache memoryCache) CloudResponseStore(kind string, selector *models.Selector, response *models.CloudResponse) error {
+	cache[kind+" "+selector.String()] = response
+	return nil
+}
+
+func (cache memoryCache) CloudResponsesDelete(space *models.Selector) error {
+	for key := range cache {
+		delete(cache, key)
+	}
+	return nil
+}
+
+// cachedCloud returns a client of a fake cloud serving a space, counting the requests it receives and
+// how many of them were answered as not modified
+func cachedCloud(t *testing.T) (*models.Cloud, *int, *int) {
+	requests, notModified := 0, 0
+	commands := []*models.Command{
+		{Meta: models.Meta{ID: "list@test:default"}, Label: "list", Code: "ls"},
+		{Meta: models.Meta{ID: "processes@test:default"}, Label: "processes", Code: "ps"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.URL.Path == "/v1/spaces" {
+			w.Write([]byte(`{"ID": "@test:default", "Label": "default"}`))
+			return
+		}
+		data, _ := json.Marshal(commands)
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	baseURL, _ := url.Parse(server.URL)
+	cloud := models.Cloud{
+		BaseURL:    baseURL,
+		HttpClient: http.DefaultClient,
+		Cbox:       &models.CBox{},
+		Cache:      memoryCache{},
+		CacheTTL:   time.Hour,
+	}
+	return &cloud, &requests, &notModified
+}
+
+func TestCloudCache(t *testing.T) {
+	cloud, requests, notModified := cachedCloud(t)
+	space, _ := models.ParseSelectorForCloud("@test:default")
+	item, _ := models.ParseSelectorForCloud("processes@test:default")
+
+	for i := 0; i < 2; i++ {
+		if commands, err := cloud.CommandList(space); err != nil || len(commands) != 2 {
+			t.Fatalf("could not list commands: %v", err)
+		}
+	}
+	if command, err := cloud.CommandFind(item); err != nil || command.Code != "ps" {
+		t.Fatalf("could not find command: %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("fresh responses should be reused, got %d requests", *requests)
+	}
+
+	cloud.CacheTTL = 0
+	if commands, err := cloud.CommandList(space); err != nil || len(commands) != 2 || *requests != 2 || *notModified != 1 {
+		t.Errorf("stale responses should be revalidated (error: %v, requests: %d, not modified: %d)", err, *requests, *notModified)
+	}
+
+	if err := cloud.SpacePublish(&models.Space{Meta: models.Meta{Selector: space}}); err != nil {
+		t.Fatalf("could not publish space: %v", err)
+	}
+	cloud.CacheTTL = time.Hour
+	cloud.CommandList(space)
+	if *requests != 4 || *notModified != 1 {
+		t.Errorf("publishing should forget the responses cached, got %d requests", *requests)
+	}
+}
+
+func TestCloudOffline(t *testing.T) {
+	cloud, requests, _ := cachedCloud(t)
+	space, _ := models.ParseSelectorForCloud("@test:default")
+	item, _ := models.ParseSelectorForCloud("processes@test:default")
+
+	cloud.CommandList(space)
+
+	cloud.Offline = true
+	cloud.CacheTTL = 0
+	if command, err := cloud.CommandFind(item); err != nil || command.Code != "ps" {
+		t.Errorf("cached commands should be found offline: %v", err)
+	}
+	if _, err := cloud.SpaceFind(space); !errors.Is(err, models.ErrCloudOffline) {
+		t.Errorf("responses not cached should fail offline, got %v", err)
+	}
+	if err := cloud.SpacePublish(&models.Space{Meta: models.Meta{Selector: space}}); !errors.Is(err, models.ErrCloudOffline) {
+		t.Errorf("publishing should fail offline, got %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("the cloud should not be reached offline, got %d requests", *requests)
+	}
+}
+
+// flakyCloud returns a client of a fake cloud answering the first requests with the statuses given (and
+// then with an empty list), counting the requests it receives
+func flakyCloud(t *testing.T, statuses ...int) (*models.Cloud, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= len(statuses) {
+			switch statuses[requests-1] {
+			case http.StatusTooManyRequests:
+				w.Header().Set("Retry-After", "0")
+			case http.StatusGatewayTimeout:
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.WriteHeader(statuses[requests-1])
+			return
+		}
+		w.Write([]byte("[]"))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := tools.NewHTTPClient(tools.HTTPOptions{ConnectTimeout: time.Second, ReadTimeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	baseURL, _ := url.Parse(server.URL)
+	cloud := models.Cloud{BaseURL: baseURL, HttpClient: client, Cbox: &models.CBox{}, Retries: 3, RetryWait: time.Millisecond}
+	return &cloud, &requests
+}
+
+func TestCloudRetries(t *testing.T) {
+	space, _ := models.ParseSelectorForCloud("@test:default")
+
+	cloud, requests := flakyCloud(t, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusTooManyRequests)
+	if _, err := cloud.CommandList(space); err != nil || *requests != 4 {
+		t.Errorf("server errors, timeouts and rate limits should be retried (error: %v, requests: %d)", err, *requests)
+	}
+
+	cloud, requests = flakyCloud(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	if _, err := cloud.CommandList(space); err == nil || *requests != 4 {
+		t.Errorf("requests should be retried up to 3 times (error: %v, requests: %d)", err, *requests)
+	}
+
+	cloud, requests = flakyCloud(t, http.StatusServiceUnavailable)
+	if err := cloud.SpacePublish(&models.Space{Meta: models.Meta{Selector: space}}); err == nil || *requests != 1 {
+		t.Errorf("publishing is not idempotent, so it should not be retried on server errors (error: %v, requests: %d)", err, *requests)
+	}
+
+	cloud, requests = flakyCloud(t, http.StatusTooManyRequests)
+	if err := cloud.SpacePublish(&models.Space{Meta: models.Meta{Selector: space}}); err != nil || *requests != 2 {
+		t.Errorf("requests exceeding the rate limit should be retried (error: %v, requests: %d)", err, *requests)
+	}
+
+	cloud, requests = flakyCloud(t, http.StatusNotFound)
+	if _, err := cloud.SpaceFind(space); !errors.Is(err, models.ErrCloudNotFound) || *requests != 1 {
+		t.Errorf("client errors should not be retried (error: %v, requests: %d)", err, *requests)
+	}
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dplabs/cbox/src/tools"
+)
+
+const (
+	DiffAdded     = "added"
+	DiffChanged   = "changed"
+	DiffRemoved   = "removed"
+	DiffUnchanged = "unchanged"
+
+	diffContext = 3
+)
+
+// SpaceDiff compares the commands of a local space with the ones of its cloud counterpart: what would
+// change remotely if the local space was published
+type SpaceDiff struct {
+	Space     string         `json:"space"`
+	Remote    string         `json:"remote"`
+	Published bool           `json:"published"`
+	Commands  []*CommandDiff `json:"commands"`
+	Summary   DiffSummary    `json:"summary"`
+}
+
+// CommandDiff compares the local and cloud versions of a command: added if only found locally, removed
+// if only found in the cloud. Diff holds their differences in unified format
+type CommandDiff struct {
+	Label  string   `json:"label"`
+	Status string   `json:"status"`
+	Fields []string `json:"fields"`
+	Diff   string   `json:"diff"`
+}
+
+// DiffSummary counts the commands of each status
+type DiffSummary struct {
+	Added     int `json:"added"`
+	Changed   int `json:"changed"`
+	Removed   int `json:"removed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// DiffSpace matches by label the commands of a local space and of its cloud counterpart, comparing their
+// code and metadata (description, URL and tags)
+func DiffSpace(space *Selector, remote *Selector, local []*Command, cloud []*Command) *SpaceDiff {
+	diff := SpaceDiff{Space: space.String(), Remote: remote.String(), Commands: []*CommandDiff{}}
+
+	localCommands := commandsByLabel(local)
+	cloudCommands := commandsByLabel(cloud)
+
+	labels := []string{}
+	for label := range localCommands {
+		labels = append(labels, label)
+	}
+	for label := range cloudCommands {
+		if _, found := localCommands[label]; !found {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		mine, theirs := localCommands[label], cloudCommands[label]
+
+		command := CommandDiff{Label: label, Fields: []string{}}
+		switch {
+		case theirs == nil:
+			command.Status = DiffAdded
+		case mine == nil:
+			command.Status = DiffRemoved
+		default:
+			for _, field := range []string{SearchFieldDescription, SearchFieldURL, SearchFieldTag, SearchFieldCode} {
+				if fieldText(mine, field) != fieldText(theirs, field) {
+					command.Fields = append(command.Fields, field)
+				}
+			}
+			command.Status = DiffChanged
+			if len(command.Fields) == 0 {
+				command.Status = DiffUnchanged
+			}
+		}
+
+		switch command.Status {
+		case DiffAdded:
+			diff.Summary.Added++
+		case DiffRemoved:
+			diff.Summary.Removed++
+		case DiffChanged:
+			diff.Summary.Changed++
+		case DiffUnchanged:
+			diff.Summary.Unchanged++
+		}
+
+		command.Diff = tools.UnifiedDiff(diffDocument(theirs), diffDocument(mine), diffName(remote, label, theirs), diffName(space, label, mine), diffContext)
+		diff.Commands = append(diff.Commands, &command)
+	}
+
+	return &diff
+}
+
+// diffDocument renders the fields of a command compared by diffs: its metadata, then its code (nothing
+// if not found)
+func diffDocument(command *Command) string {
+	if command == nil {
+		return ""
+	}
+	return fmt.Sprintf("description: %s\nurl: %s\ntags: %s\n---\n%s\n", command.Description, command.URL, strings.Join(command.Tags, ", "), command.Code)
+}
+
+func diffName(space *Selector, label string, command *Command) string {
+	if command == nil {
+		return "/dev/null"
+	}
+	return space.CloneForItem(label).String()
+}
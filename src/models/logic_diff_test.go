@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+func TestDiffSpace(t *testing.T) {
+	space := models.NewSelector(models.TypeNone, "", "work", "")
+	remote := models.NewSelector(models.TypeUser, "test", "work", "")
+
+	local := []*models.Command{
+		{Label: "same", Code: "ls"},
+		{Label: "edited", Code: "ps aux", Description: "Processes", Tags: []string{"ps"}},
+		{Label: "new", Code: "uptime"},
+	}
+	cloud := []*models.Command{
+		{Label: "same", Code: "ls"},
+		{Label: "edited", Code: "ps", Description: "Processes"},
+		{Label: "gone", Code: "df"},
+	}
+
+	diff := models.DiffSpace(space, remote, local, cloud)
+
+	expected := map[string]string{"same": models.DiffUnchanged, "edited": models.DiffChanged, "new": models.DiffAdded, "gone": models.DiffRemoved}
+	if len(diff.Commands) != len(expected) {
+		t.Fatalf("expected %d commands, got %d", len(expected), len(diff.Commands))
+	}
+	for _, command := range diff.Commands {
+		if command.Status != expected[command.Label] {
+			t.Errorf("%s: expected %s, got %s", command.Label, expected[command.Label], command.Status)
+		}
+	}
+
+	if diff.Summary != (models.DiffSummary{Added: 1, Changed: 1, Removed: 1, Unchanged: 1}) {
+		t.Errorf("unexpected summary: %+v", diff.Summary)
+	}
+
+	edited := diff.Commands[0]
+	if edited.Label != "edited" || strings.Join(edited.Fields, ",") != "tag,code" {
+		t.Fatalf("unexpected changed fields: %+v", edited)
+	}
+	for _, line := range []string{"--- edited@test:work", "+++ edited@work", "-tags: ", "+tags: ps", "-ps", "+ps aux"} {
+		if !strings.Contains(edited.Diff, line+"\n") {
+			t.Errorf("diff should contain %q:\n%s", line, edited.Diff)
+		}
+	}
+}
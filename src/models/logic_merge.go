@@ -0,0 +1,164 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MergeMine   = "mine"
+	MergeTheirs = "theirs"
+	MergeEdited = "edited"
+
+	conflictMarkerMine   = "<<<<<<< mine"
+	conflictMarkerBase   = "======="
+	conflictMarkerTheirs = ">>>>>>> theirs"
+)
+
+// fields merged as a whole text (tags are merged one by one, so they never conflict)
+var mergeTextFields = []string{SearchFieldCode, SearchFieldDescription, SearchFieldURL}
+
+// FieldConflict is a field changed differently on both sides since the version they had in common
+type FieldConflict struct {
+	Field  string
+	Base   string
+	Mine   string
+	Theirs string
+}
+
+// CommandMerge is the result of merging the changes done to a command on two sides: Merged holds the
+// changes done on any of them, and mine's value for the fields in conflict until they are resolved
+type CommandMerge struct {
+	Label     string
+	Mine      *Command
+	Theirs    *Command
+	Merged    *Command
+	Conflicts []*FieldConflict
+}
+
+// MergeCommand merges field by field (code, description, URL and tags) the changes done to a command
+// on two sides since base, the version both had in common (nil if unknown, so every field holding
+// different values is a conflict)
+func MergeCommand(base *Command, mine *Command, theirs *Command) *CommandMerge {
+	merged := *mine
+	merged.Tags = mergeTags(base, mine, theirs)
+	if theirs.UpdatedAt.After(mine.UpdatedAt) {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+
+	merge := CommandMerge{Label: mine.Label, Mine: mine, Theirs: theirs, Merged: &merged, Conflicts: []*FieldConflict{}}
+	for _, field := range mergeTextFields {
+		m, t := fieldText(mine, field), fieldText(theirs, field)
+		switch {
+		case m == t:
+		case base != nil && m == fieldText(base, field):
+			setFieldText(&merged, field, t)
+		case base != nil && t == fieldText(base, field):
+		default:
+			conflict := FieldConflict{Field: field, Mine: m, Theirs: t}
+			if base != nil {
+				conflict.Base = fieldText(base, field)
+			}
+			merge.Conflicts = append(merge.Conflicts, &conflict)
+		}
+	}
+	return &merge
+}
+
+// mergeTags keeps the tags both sides have, and the ones added on any of them since base (so removing a
+// tag on any side removes it)
+func mergeTags(base *Command, mine *Command, theirs *Command) []string {
+	inBase := map[string]bool{}
+	if base != nil {
+		for _, tag := range base.Tags {
+			inBase[tag] = true
+		}
+	}
+	inMine := map[string]bool{}
+	for _, tag := range mine.Tags {
+		inMine[tag] = true
+	}
+	inTheirs := map[string]bool{}
+	for _, tag := range theirs.Tags {
+		inTheirs[tag] = true
+	}
+
+	tags := []string{}
+	for _, tag := range mine.Tags {
+		if inTheirs[tag] || !inBase[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range theirs.Tags {
+		if !inMine[tag] && !inBase[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Resolved tells if every field could be merged
+func (merge *CommandMerge) Resolved() bool {
+	return len(merge.Conflicts) == 0
+}
+
+// Newest returns the side whose version was updated last (mine on ties)
+func (merge *CommandMerge) Newest() string {
+	if merge.Theirs.UpdatedAt.After(merge.Mine.UpdatedAt) {
+		return MergeTheirs
+	}
+	return MergeMine
+}
+
+// Resolve returns the merged command, keeping the value of one of the sides (MergeMine or MergeTheirs)
+// for the fields in conflict
+func (merge *CommandMerge) Resolve(side string) *Command {
+	resolved := *merge.Merged
+	resolved.Tags = append([]string{}, merge.Merged.Tags...)
+	for _, conflict := range merge.Conflicts {
+		if side == MergeTheirs {
+			setFieldText(&resolved, conflict.Field, conflict.Theirs)
+		} else {
+			setFieldText(&resolved, conflict.Field, conflict.Mine)
+		}
+	}
+	return &resolved
+}
+
+// WithConflictMarkers returns the merged command holding both values of the fields in conflict,
+// delimited by conflict markers, to be edited by hand
+func (merge *CommandMerge) WithConflictMarkers() *Command {
+	marked := merge.Resolve(MergeMine)
+	for _, conflict := range merge.Conflicts {
+		setFieldText(marked, conflict.Field, fmt.Sprintf("%s\n%s\n%s\n%s\n%s", conflictMarkerMine, conflict.Mine, conflictMarkerBase, conflict.Theirs, conflictMarkerTheirs))
+	}
+	return marked
+}
+
+// HasConflictMarkers checks if any field of a command still holds conflict markers
+func HasConflictMarkers(command *Command) bool {
+	for _, field := range mergeTextFields {
+		for _, line := range strings.Split(fieldText(command, field), "\n") {
+			line = strings.TrimSpace(line)
+			if line == conflictMarkerMine || line == conflictMarkerTheirs {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func fieldText(command *Command, field string) string {
+	return strings.Join(fieldTexts(command, field), " ")
+}
+
+func setFieldText(command *Command, field string, text string) {
+	switch field {
+	case SearchFieldCode:
+		command.Code = text
+	case SearchFieldDescription:
+		command.Description = text
+	case SearchFieldURL:
+		command.URL = text
+	}
+}
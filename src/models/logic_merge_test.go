@@ -0,0 +1,67 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+func mergeCommand(code string, description string, url string, tags ...string) *models.Command {
+	return &models.Command{Label: "cmd", Code: code, Description: description, URL: url, Tags: tags}
+}
+
+func TestMergeCommand(t *testing.T) {
+	base := mergeCommand("ls", "List", "", "files", "old")
+	mine := mergeCommand("ls -l", "List", "", "files", "mine")
+	theirs := mergeCommand("ls", "List files", "https://man7.org", "files", "old", "theirs")
+
+	merge := models.MergeCommand(base, mine, theirs)
+	if !merge.Resolved() {
+		t.Fatalf("changes to different fields should not conflict: %+v", merge.Conflicts)
+	}
+
+	merged := merge.Merged
+	if merged.Code != "ls -l" || merged.Description != "List files" || merged.URL != "https://man7.org" {
+		t.Errorf("unexpected merged fields: %+v", merged)
+	}
+	if tags := strings.Join(merged.Tags, ","); tags != "files,mine,theirs" {
+		t.Errorf("tags added on any side should be kept and removed ones dropped, got %s", tags)
+	}
+}
+
+func TestMergeCommandConflicts(t *testing.T) {
+	base := mergeCommand("ls", "List", "")
+	mine := mergeCommand("ls -l", "List", "")
+	theirs := mergeCommand("ls -a", "List all", "")
+
+	merge := models.MergeCommand(base, mine, theirs)
+	if len(merge.Conflicts) != 1 || merge.Conflicts[0].Field != models.SearchFieldCode || merge.Conflicts[0].Base != "ls" {
+		t.Fatalf("code changed on both sides should conflict: %+v", merge.Conflicts)
+	}
+
+	if resolved := merge.Resolve(models.MergeMine); resolved.Code != "ls -l" || resolved.Description != "List all" {
+		t.Errorf("keeping mine should keep merged fields: %+v", resolved)
+	}
+	if resolved := merge.Resolve(models.MergeTheirs); resolved.Code != "ls -a" || resolved.Description != "List all" {
+		t.Errorf("keeping theirs should keep merged fields: %+v", resolved)
+	}
+
+	marked := merge.WithConflictMarkers()
+	if !models.HasConflictMarkers(marked) || !strings.Contains(marked.Code, "ls -l") || !strings.Contains(marked.Code, "ls -a") {
+		t.Errorf("conflicting code should hold both versions between markers: %q", marked.Code)
+	}
+	if models.HasConflictMarkers(merge.Merged) {
+		t.Errorf("merged command should not hold conflict markers")
+	}
+}
+
+func TestMergeCommandWithoutBase(t *testing.T) {
+	merge := models.MergeCommand(nil, mergeCommand("ls", "List", "", "a"), mergeCommand("ls", "List files", "", "b"))
+	if len(merge.Conflicts) != 1 || merge.Conflicts[0].Field != models.SearchFieldDescription {
+		t.Errorf("every field holding different values should conflict without a base: %+v", merge.Conflicts)
+	}
+	if tags := strings.Join(merge.Merged.Tags, ","); tags != "a,b" {
+		t.Errorf("tags of both sides should be kept without a base, got %s", tags)
+	}
+}
@@ -0,0 +1,441 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dplabs/cbox/src/tools"
+)
+
+const (
+	queryOperatorAnd = "AND"
+	queryOperatorOr  = "OR"
+	queryOperatorNot = "NOT"
+
+	// how good a match is, from a term matching the whole field to one only matching with some typos
+	queryQualityExact     = 4
+	queryQualityWordStart = 3
+	queryQualityContains  = 2
+	queryQualityTypo      = 1
+
+	queryTypoMinLength = 4
+)
+
+// fields looked up by terms not qualified with a field
+var queryDefaultFields = []string{SearchFieldLabel, SearchFieldTag, SearchFieldDescription, SearchFieldCode}
+
+// Query is a parsed search criteria, made of terms optionally qualified with a field (tag:k8s,
+// code:"kubectl apply"), combined with AND (implicit), OR, NOT or '-' and grouped with parenthesis
+type Query struct {
+	Text string
+	root queryNode
+}
+
+type queryNode interface {
+	score(command *Command) (int, bool)
+}
+
+type queryTerm struct {
+	field  string
+	value  string
+	phrase bool
+}
+
+type queryNot struct {
+	node queryNode
+}
+
+type queryAnd struct {
+	nodes []queryNode
+}
+
+type queryOr struct {
+	nodes []queryNode
+}
+
+// ParseQuery parses a search criteria
+func ParseQuery(text string) (*Query, error) {
+	tokens, err := tokenizeQuery(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid query: empty criteria")
+	}
+
+	parser := queryParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected '%s'", tokens[parser.position].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+
+	return &Query{Text: text, root: root}, nil
+}
+
+// Score checks if a command matches the query, returning how relevant it is
+func (query *Query) Score(command *Command) (int, bool) {
+	return query.root.score(command)
+}
+
+// Matches checks if a command matches the query
+func (query *Query) Matches(command *Command) bool {
+	_, ok := query.Score(command)
+	return ok
+}
+
+// Search returns the commands matching the query, most relevant first
+func (query *Query) Search(commands []*Command) []*Command {
+	return Rank(query, commands)
+}
+
+// Spans returns where the terms of the query (those not negated) appear in a text of a field
+func (query *Query) Spans(field string, text string) [][]int {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return nil
+	}
+
+	spans := [][]int{}
+	for _, term := range positiveTerms(query.root) {
+		if term.field == "" && !containsField(queryDefaultFields, field) || term.field != "" && term.field != field {
+			continue
+		}
+		for offset := 0; ; {
+			index := strings.Index(lower[offset:], term.value)
+			if index == -1 {
+				break
+			}
+			spans = append(spans, []int{offset + index, offset + index + len(term.value)})
+			offset = offset + index + len(term.value)
+		}
+	}
+	return spans
+}
+
+func positiveTerms(node queryNode) []*queryTerm {
+	switch n := node.(type) {
+	case *queryTerm:
+		return []*queryTerm{n}
+	case *queryAnd:
+		terms := []*queryTerm{}
+		for _, child := range n.nodes {
+			terms = append(terms, positiveTerms(child)...)
+		}
+		return terms
+	case *queryOr:
+		terms := []*queryTerm{}
+		for _, child := range n.nodes {
+			terms = append(terms, positiveTerms(child)...)
+		}
+		return terms
+	}
+	return nil
+}
+
+// QueryPhrase is a quoted text which must appear in some field of a command
+type QueryPhrase struct {
+	Field string
+	Text  string
+}
+
+// RequiredPhrases returns the phrases qualified with a field that every command matching the query
+// must contain, so storage backends can use them to narrow down the commands to check
+func (query *Query) RequiredPhrases() []QueryPhrase {
+	nodes := []queryNode{query.root}
+	if and, ok := query.root.(*queryAnd); ok {
+		nodes = and.nodes
+	}
+
+	phrases := []QueryPhrase{}
+	for _, node := range nodes {
+		if term, ok := node.(*queryTerm); ok && term.phrase && term.field != "" {
+			phrases = append(phrases, QueryPhrase{Field: term.field, Text: term.value})
+		}
+	}
+	return phrases
+}
+
+func (term *queryTerm) score(command *Command) (int, bool) {
+	fields := queryDefaultFields
+	if term.field != "" {
+		fields = []string{term.field}
+	}
+
+	best := 0
+	for _, field := range fields {
+		for _, text := range fieldTexts(command, field) {
+			if quality := term.quality(text); quality*searchFieldWeights[field] > best {
+				best = quality * searchFieldWeights[field]
+			}
+		}
+	}
+
+	return best, best > 0
+}
+
+// quality tells how well the term matches a text: 0 if it doesn't
+func (term *queryTerm) quality(text string) int {
+	text = strings.ToLower(text)
+
+	if text == term.value {
+		return queryQualityExact
+	}
+
+	if strings.Contains(text, term.value) {
+		for offset := 0; ; {
+			index := strings.Index(text[offset:], term.value)
+			if index == -1 {
+				return queryQualityContains
+			}
+			previous, _ := utf8.DecodeLastRuneInString(text[:offset+index])
+			if offset+index == 0 || isQueryWordSeparator(previous) {
+				return queryQualityWordStart
+			}
+			offset = offset + index + 1
+		}
+	}
+
+	length := utf8.RuneCountInString(term.value)
+	if term.phrase || length < queryTypoMinLength {
+		return 0
+	}
+
+	// allow one typo every four characters, comparing with whole words and with their beginning
+	typos := length / queryTypoMinLength
+	for _, word := range strings.FieldsFunc(text, isQueryWordSeparator) {
+		runes := []rune(word)
+		for prefix := length - typos; prefix <= length+typos && prefix <= len(runes); prefix++ {
+			if tools.EditDistance(term.value, string(runes[:prefix])) <= typos {
+				return queryQualityTypo
+			}
+		}
+	}
+	return 0
+}
+
+func isQueryWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func (not *queryNot) score(command *Command) (int, bool) {
+	_, ok := not.node.score(command)
+	return 0, !ok
+}
+
+func (and *queryAnd) score(command *Command) (int, bool) {
+	total := 0
+	for _, node := range and.nodes {
+		score, ok := node.score(command)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+func (or *queryOr) score(command *Command) (int, bool) {
+	best := 0
+	found := false
+	for _, node := range or.nodes {
+		if score, ok := node.score(command); ok {
+			found = true
+			if score > best {
+				best = score
+			}
+		}
+	}
+	return best, found
+}
+
+const (
+	queryTokenTerm = iota
+	queryTokenAnd
+	queryTokenOr
+	queryTokenNot
+	queryTokenOpen
+	queryTokenClose
+)
+
+type queryToken struct {
+	kind int
+	text string
+	term *queryTerm
+}
+
+func tokenizeQuery(text string) ([]queryToken, error) {
+	tokens := []queryToken{}
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: queryTokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: queryTokenClose, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, queryToken{kind: queryTokenNot, text: "-"})
+			i++
+		default:
+			start := i
+			field := ""
+			for j := i; j < len(runes) && unicode.IsLetter(runes[j]); j++ {
+				if j+1 < len(runes) && runes[j+1] == ':' {
+					if alias, found := searchFieldAliases[strings.ToLower(string(runes[i:j+1]))]; found {
+						field = alias
+						i = j + 2
+					}
+					break
+				}
+			}
+
+			value := ""
+			phrase := false
+			if i < len(runes) && runes[i] == '"' {
+				end := i + 1
+				for end < len(runes) && runes[end] != '"' {
+					end++
+				}
+				if end == len(runes) {
+					return nil, fmt.Errorf("missing closing quote in '%s'", string(runes[start:]))
+				}
+				value = string(runes[i+1 : end])
+				phrase = true
+				i = end + 1
+			} else {
+				end := i
+				for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' {
+					end++
+				}
+				value = string(runes[i:end])
+				i = end
+			}
+
+			word := string(runes[start:i])
+			if field == "" && !phrase {
+				switch word {
+				case queryOperatorAnd:
+					tokens = append(tokens, queryToken{kind: queryTokenAnd, text: word})
+					continue
+				case queryOperatorOr:
+					tokens = append(tokens, queryToken{kind: queryTokenOr, text: word})
+					continue
+				case queryOperatorNot:
+					tokens = append(tokens, queryToken{kind: queryTokenNot, text: word})
+					continue
+				}
+			}
+
+			if value == "" {
+				return nil, fmt.Errorf("no value given in '%s'", word)
+			}
+			term := &queryTerm{field: field, value: strings.ToLower(value), phrase: phrase}
+			tokens = append(tokens, queryToken{kind: queryTokenTerm, text: word, term: term})
+		}
+	}
+
+	return tokens, nil
+}
+
+// queryParser builds the tree of a query, where NOT binds tighter than AND, and AND tighter than OR
+type queryParser struct {
+	tokens   []queryToken
+	position int
+}
+
+func (parser *queryParser) peek() *queryToken {
+	if parser.position < len(parser.tokens) {
+		return &parser.tokens[parser.position]
+	}
+	return nil
+}
+
+func (parser *queryParser) parseOr() (queryNode, error) {
+	nodes := []queryNode{}
+	for {
+		node, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		if token := parser.peek(); token == nil || token.kind != queryTokenOr {
+			break
+		}
+		parser.position++
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &queryOr{nodes: nodes}, nil
+}
+
+func (parser *queryParser) parseAnd() (queryNode, error) {
+	nodes := []queryNode{}
+	for {
+		node, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		token := parser.peek()
+		if token == nil || token.kind == queryTokenOr || token.kind == queryTokenClose {
+			break
+		}
+		if token.kind == queryTokenAnd {
+			parser.position++
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &queryAnd{nodes: nodes}, nil
+}
+
+func (parser *queryParser) parseNot() (queryNode, error) {
+	token := parser.peek()
+	if token == nil {
+		return nil, fmt.Errorf("unexpected end of criteria")
+	}
+
+	switch token.kind {
+	case queryTokenNot:
+		parser.position++
+		node, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNot{node: node}, nil
+
+	case queryTokenOpen:
+		parser.position++
+		node, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if token := parser.peek(); token == nil || token.kind != queryTokenClose {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		parser.position++
+		return node, nil
+
+	case queryTokenTerm:
+		parser.position++
+		return token.term, nil
+	}
+
+	return nil, fmt.Errorf("unexpected '%s'", token.text)
+}
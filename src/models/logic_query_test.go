@@ -0,0 +1,108 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+var queryCommands = []*models.Command{
+	{
+		Label:       "apply-manifests",
+		Description: "Apply every manifest in the current directory",
+		Code:        "kubectl apply -f .",
+		URL:         "https://kubernetes.io/docs",
+		Tags:        []string{"k8s"},
+	},
+	{
+		Label:       "deploy",
+		Description: "Deploy the application",
+		Code:        "helm upgrade --install app ./chart",
+		URL:         "https://github.com/helm/helm",
+		Tags:        []string{"k8s", "old"},
+	},
+	{
+		Label:       "list-containers",
+		Description: "List running containers, to deploy them somewhere else",
+		Code:        "docker ps",
+		Tags:        []string{"docker"},
+	},
+}
+
+func searchLabels(t *testing.T, criteria string) []string {
+	query, err := models.ParseQuery(criteria)
+	if err != nil {
+		t.Fatalf("could not parse query '%s': %v", criteria, err)
+	}
+
+	labels := []string{}
+	for _, command := range query.Search(queryCommands) {
+		labels = append(labels, command.Label)
+	}
+	return labels
+}
+
+func assertSearch(t *testing.T, criteria string, expected ...string) {
+	labels := searchLabels(t, criteria)
+	if len(labels) != len(expected) {
+		t.Errorf("query '%s': expected %v, got %v", criteria, expected, labels)
+		return
+	}
+	for i := range expected {
+		if labels[i] != expected[i] {
+			t.Errorf("query '%s': expected %v, got %v", criteria, expected, labels)
+			return
+		}
+	}
+}
+
+func TestQueryFields(t *testing.T) {
+	assertSearch(t, "tag:k8s", "apply-manifests", "deploy")
+	assertSearch(t, "tag:k8s -tag:old", "apply-manifests")
+	assertSearch(t, "label:deploy", "deploy")
+	assertSearch(t, `code:"kubectl apply"`, "apply-manifests")
+	assertSearch(t, `code:"apply kubectl"`)
+	assertSearch(t, "url:github", "deploy")
+	assertSearch(t, "desc:containers", "list-containers")
+}
+
+func TestQueryOperators(t *testing.T) {
+	assertSearch(t, "tag:docker OR label:deploy", "deploy", "list-containers")
+	assertSearch(t, "tag:k8s AND NOT tag:old", "apply-manifests")
+	assertSearch(t, "(tag:docker OR tag:old) -label:deploy", "list-containers")
+	assertSearch(t, "-(tag:docker OR tag:old)", "apply-manifests")
+}
+
+func TestQueryRanking(t *testing.T) {
+	// a label match beats a description match
+	assertSearch(t, "deploy", "deploy", "list-containers")
+	// a tag match beats a code one
+	assertSearch(t, "docker", "list-containers")
+}
+
+func TestQueryTypos(t *testing.T) {
+	assertSearch(t, "kubetcl", "apply-manifests")
+	assertSearch(t, "contianers", "list-containers")
+	assertSearch(t, `"contianers"`)
+	assertSearch(t, "dpl")
+}
+
+func TestQueryInvalid(t *testing.T) {
+	for _, criteria := range []string{"", "   ", "(tag:k8s", "tag:k8s)", `code:"kubectl`, "tag:", "OR tag:k8s", "tag:k8s NOT"} {
+		if _, err := models.ParseQuery(criteria); err == nil {
+			t.Errorf("query '%s' should be invalid", criteria)
+		}
+	}
+}
+
+func TestQueryRequiredPhrases(t *testing.T) {
+	query, err := models.ParseQuery(`code:"kubectl apply" (label:"deploy" OR tag:k8s) "manifest" -code:"helm"`)
+	if err != nil {
+		t.Fatalf("could not parse query: %v", err)
+	}
+
+	phrases := query.RequiredPhrases()
+	if len(phrases) != 1 || phrases[0].Field != models.SearchFieldCode || phrases[0].Text != "kubectl apply" {
+		t.Errorf("unexpected required phrases: %v", phrases)
+	}
+}
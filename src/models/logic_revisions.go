@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+)
+
+func (command *Command) revision(author string, createdAt UnixTime) Revision {
+	return Revision{
+		Number:      len(command.Revisions) + 1,
+		Code:        command.Code,
+		Description: command.Description,
+		Tags:        append([]string{}, command.Tags...),
+		Author:      author,
+		CreatedAt:   createdAt,
+	}
+}
+
+func (revision *Revision) sameContent(command *Command) bool {
+	if revision.Code != command.Code || revision.Description != command.Description || len(revision.Tags) != len(command.Tags) {
+		return false
+	}
+	for i, tag := range revision.Tags {
+		if tag != command.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// revise appends a new revision to the command if its content changed. Commands without revisions
+// (created before revisions were tracked) get their previous content recorded first, so it's not lost
+func (command *Command) revise(previous *Command, author string, now UnixTime) {
+	if len(command.Revisions) == 0 {
+		command.Revisions = []Revision{previous.revision("", previous.UpdatedAt)}
+	}
+
+	last := command.Revisions[len(command.Revisions)-1]
+	if !last.sameContent(command) {
+		command.Revisions = append(command.Revisions, command.revision(author, now))
+	}
+}
+
+func (command *Command) RevisionFind(number int) (*Revision, error) {
+	for i := range command.Revisions {
+		if command.Revisions[i].Number == number {
+			return &command.Revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found for command '%s'", number, command.Label)
+}
@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+func TestCommandEditRecordsRevisions(t *testing.T) {
+	command := &models.Command{Label: "label", Code: "v1", Tags: []string{"tag"}}
+	space := &models.Space{Entries: []*models.Command{command}}
+
+	previous := *command
+	command.Label = "renamed"
+	if err := space.CommandEdit(command, previous, "someone"); err != nil {
+		t.Fatal(err)
+	}
+	if len(command.Revisions) != 1 {
+		t.Fatalf("renaming a command should only record its original content, got %d revisions", len(command.Revisions))
+	}
+
+	previous = *command
+	command.Code = "v2"
+	if err := space.CommandEdit(command, previous, "someone"); err != nil {
+		t.Fatal(err)
+	}
+	if len(command.Revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(command.Revisions))
+	}
+	if r := command.Revisions[1]; r.Number != 2 || r.Code != "v2" || r.Author != "someone" {
+		t.Errorf("unexpected last revision: %+v", r)
+	}
+
+	if err := space.CommandRevert(command, 1, "another"); err != nil {
+		t.Fatal(err)
+	}
+	if command.Code != "v1" {
+		t.Errorf("command not reverted, code is '%s'", command.Code)
+	}
+	if len(command.Revisions) != 3 || command.Revisions[2].Author != "another" {
+		t.Errorf("reverting should record a new revision, got %+v", command.Revisions)
+	}
+
+	if err := space.CommandRevert(command, 42, ""); err == nil {
+		t.Errorf("reverting to an unknown revision should fail")
+	}
+}
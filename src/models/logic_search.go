@@ -0,0 +1,233 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	SearchFieldLabel       = "label"
+	SearchFieldDescription = "description"
+	SearchFieldTag         = "tag"
+	SearchFieldCode        = "code"
+	SearchFieldURL         = "url"
+
+	// CaseSmart ignores case unless the pattern contains some upper case letter
+	CaseSmart       = "smart"
+	CaseSensitive   = "sensitive"
+	CaseInsensitive = "insensitive"
+)
+
+var (
+	// weight of every field when ranking results, so label matches beat description or code ones
+	searchFieldWeights = map[string]int{
+		SearchFieldLabel:       8,
+		SearchFieldTag:         6,
+		SearchFieldDescription: 4,
+		SearchFieldURL:         2,
+		SearchFieldCode:        1,
+	}
+
+	searchFieldAliases = map[string]string{
+		"label":       SearchFieldLabel,
+		"desc":        SearchFieldDescription,
+		"description": SearchFieldDescription,
+		"tag":         SearchFieldTag,
+		"tags":        SearchFieldTag,
+		"code":        SearchFieldCode,
+		"url":         SearchFieldURL,
+	}
+)
+
+// Matcher decides which commands are found by a search, how relevant they are and which parts of
+// their fields matched
+type Matcher interface {
+	Score(command *Command) (int, bool)
+	Spans(field string, text string) [][]int
+}
+
+// Rank returns the commands found by a matcher, most relevant first
+func Rank(matcher Matcher, commands []*Command) []*Command {
+	scores := map[*Command]int{}
+	results := []*Command{}
+	for _, command := range commands {
+		if score, ok := matcher.Score(command); ok {
+			scores[command] = score
+			results = append(results, command)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return scores[results[i]] > scores[results[j]] })
+	return results
+}
+
+// SearchFields returns the fields of a command that can be searched
+func SearchFields() []string {
+	return []string{SearchFieldLabel, SearchFieldDescription, SearchFieldTag, SearchFieldCode, SearchFieldURL}
+}
+
+// ParseSearchField validates the name of a field (or any of its aliases, like 'desc')
+func ParseSearchField(name string) (string, error) {
+	field, found := searchFieldAliases[strings.ToLower(name)]
+	if !found {
+		return "", fmt.Errorf("unknown field '%s' (valid ones: %s)", name, strings.Join(SearchFields(), ", "))
+	}
+	return field, nil
+}
+
+func fieldTexts(command *Command, field string) []string {
+	switch field {
+	case SearchFieldLabel:
+		return []string{command.Label}
+	case SearchFieldDescription:
+		return []string{command.Description}
+	case SearchFieldTag:
+		return command.Tags
+	case SearchFieldCode:
+		return []string{command.Code}
+	case SearchFieldURL:
+		return []string{command.URL}
+	}
+	return nil
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// RegexMatcher finds the commands where a regular expression matches any of the fields given
+type RegexMatcher struct {
+	Regexp *regexp.Regexp
+	Fields []string
+}
+
+// NewRegexMatcher compiles a regular expression to look for in some fields of commands (all of them if
+// none given), using any of the case modes (CaseSmart if empty)
+func NewRegexMatcher(pattern string, fields []string, caseMode string) (*RegexMatcher, error) {
+	matcher := RegexMatcher{Fields: []string{}}
+
+	if len(fields) == 0 {
+		fields = SearchFields()
+	}
+	for _, name := range fields {
+		field, err := ParseSearchField(name)
+		if err != nil {
+			return nil, err
+		}
+		if !containsField(matcher.Fields, field) {
+			matcher.Fields = append(matcher.Fields, field)
+		}
+	}
+
+	switch caseMode {
+	case "", CaseSmart:
+		if !hasUpperCase(pattern) {
+			pattern = "(?i)" + pattern
+		}
+	case CaseInsensitive:
+		pattern = "(?i)" + pattern
+	case CaseSensitive:
+	default:
+		return nil, fmt.Errorf("unknown case mode '%s' (valid ones: %s, %s, %s)", caseMode, CaseSmart, CaseSensitive, CaseInsensitive)
+	}
+
+	var err error
+	if matcher.Regexp, err = regexp.Compile(pattern); err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %v", err)
+	}
+	return &matcher, nil
+}
+
+// hasUpperCase checks if a pattern contains upper case letters, ignoring escaped ones (like \S or \W)
+func hasUpperCase(pattern string) bool {
+	escaped := false
+	for _, r := range pattern {
+		if !escaped && unicode.IsUpper(r) {
+			return true
+		}
+		escaped = !escaped && r == '\\'
+	}
+	return false
+}
+
+// Score checks if the regular expression matches any of the fields, ranking higher matches in more
+// relevant fields
+func (matcher *RegexMatcher) Score(command *Command) (int, bool) {
+	score := 0
+	for _, field := range matcher.Fields {
+		for _, text := range fieldTexts(command, field) {
+			if matcher.Regexp.MatchString(text) {
+				score += searchFieldWeights[field]
+				break
+			}
+		}
+	}
+	return score, score > 0
+}
+
+// Spans returns where the regular expression matches a text of a field
+func (matcher *RegexMatcher) Spans(field string, text string) [][]int {
+	if !containsField(matcher.Fields, field) {
+		return nil
+	}
+
+	spans := [][]int{}
+	for _, span := range matcher.Regexp.FindAllStringIndex(text, -1) {
+		if span[1] > span[0] {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// SearchResults are the commands found within a space
+type SearchResults struct {
+	Space    *Selector
+	Remote   bool
+	Commands []*Command
+}
+
+// GroupBySpace splits commands by the space they belong to, keeping their order (so spaces holding the
+// most relevant commands come first)
+func GroupBySpace(commands []*Command) []*SearchResults {
+	groups := []*SearchResults{}
+	found := map[string]*SearchResults{}
+	for _, command := range commands {
+		space := command.Selector.CloneForItem("")
+		group, ok := found[space.String()]
+		if !ok {
+			group = &SearchResults{Space: space, Commands: []*Command{}}
+			found[space.String()] = group
+			groups = append(groups, group)
+		}
+		group.Commands = append(group.Commands, command)
+	}
+	return groups
+}
+
+// ParseNamespaceFilter parses a namespace given to filter spaces: 'name/' for organizations, 'name:' for
+// users or just 'name' for any of them
+func ParseNamespaceFilter(filter string) (int, string, error) {
+	namespaceType := TypeNone
+	namespace := filter
+	if strings.HasSuffix(filter, "/") {
+		namespaceType = TypeOrganization
+		namespace = strings.TrimSuffix(filter, "/")
+	} else if strings.HasSuffix(filter, ":") {
+		namespaceType = TypeUser
+		namespace = strings.TrimSuffix(filter, ":")
+	}
+
+	if namespace == "" || strings.ContainsAny(namespace, "@/:") {
+		return TypeNone, "", fmt.Errorf("invalid namespace '%s'", filter)
+	}
+	return namespaceType, namespace, nil
+}
@@ -0,0 +1,94 @@
+package models_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+var portForward = &models.Command{
+	Label:       "tunnel-db",
+	Description: "Forward the remote DB port",
+	Code:        "ssh bastion -L 5432:db:5432",
+	Tags:        []string{"ssh"},
+}
+
+func TestRegexMatcherFields(t *testing.T) {
+	matcher, err := models.NewRegexMatcher(`ssh .* -L [0-9]+`, nil, "")
+	if err != nil {
+		t.Fatalf("could not create matcher: %v", err)
+	}
+	if !reflect.DeepEqual(matcher.Fields, models.SearchFields()) {
+		t.Errorf("every field should be searched by default, got %v", matcher.Fields)
+	}
+	if _, ok := matcher.Score(portForward); !ok {
+		t.Errorf("regex did not match the code of the command")
+	}
+
+	matcher, _ = models.NewRegexMatcher(`ssh .* -L [0-9]+`, []string{"label", "desc"}, "")
+	if _, ok := matcher.Score(portForward); ok {
+		t.Errorf("regex should only be matched with label and description")
+	}
+
+	if _, err := models.NewRegexMatcher(`ssh`, []string{"body"}, ""); err == nil {
+		t.Errorf("unknown fields should not be accepted")
+	}
+	if _, err := models.NewRegexMatcher(`ssh (`, nil, ""); err == nil {
+		t.Errorf("invalid regular expressions should not be accepted")
+	}
+}
+
+func TestRegexMatcherCase(t *testing.T) {
+	for _, test := range []struct {
+		pattern  string
+		caseMode string
+		match    bool
+	}{
+		{`forward`, "", true},
+		{`db`, models.CaseSmart, true},
+		{`DB`, models.CaseSmart, true},
+		{`Db`, models.CaseSmart, false},
+		{`\S+ DB`, models.CaseSmart, true},
+		{`forward`, models.CaseSensitive, false},
+		{`FORWARD`, models.CaseInsensitive, true},
+	} {
+		matcher, err := models.NewRegexMatcher(test.pattern, []string{"description"}, test.caseMode)
+		if err != nil {
+			t.Fatalf("could not create matcher: %v", err)
+		}
+		if _, ok := matcher.Score(portForward); ok != test.match {
+			t.Errorf("regex '%s' (case '%s'): expected match %v", test.pattern, test.caseMode, test.match)
+		}
+	}
+
+	if _, err := models.NewRegexMatcher(`db`, nil, "upper"); err == nil {
+		t.Errorf("unknown case modes should not be accepted")
+	}
+}
+
+func TestRegexMatcherSpans(t *testing.T) {
+	matcher, _ := models.NewRegexMatcher(`[0-9]+`, []string{"code"}, "")
+
+	spans := matcher.Spans(models.SearchFieldCode, portForward.Code)
+	if !reflect.DeepEqual(spans, [][]int{{15, 19}, {23, 27}}) {
+		t.Errorf("unexpected spans: %v", spans)
+	}
+	if spans := matcher.Spans(models.SearchFieldLabel, "tunnel-42"); len(spans) != 0 {
+		t.Errorf("fields not searched should not be highlighted: %v", spans)
+	}
+}
+
+func TestQuerySpans(t *testing.T) {
+	query, _ := models.ParseQuery(`code:bastion db -tag:ssh`)
+
+	if spans := query.Spans(models.SearchFieldCode, "ssh bastion -L 5432:db:5432"); !reflect.DeepEqual(spans, [][]int{{4, 11}, {20, 22}}) {
+		t.Errorf("unexpected spans in code: %v", spans)
+	}
+	if spans := query.Spans(models.SearchFieldLabel, "tunnel-bastion"); len(spans) != 0 {
+		t.Errorf("terms qualified with another field should not be highlighted: %v", spans)
+	}
+	if spans := query.Spans(models.SearchFieldTag, "ssh"); len(spans) != 0 {
+		t.Errorf("negated terms should not be highlighted: %v", spans)
+	}
+}
@@ -147,3 +147,11 @@ func NewSelector(namespaceType int, namespace string, space string, item string)
 func (selector *Selector) CloneForItem(item string) *Selector {
 	return NewSelector(selector.NamespaceType, selector.Namespace, selector.Space, item)
 }
+
+// InNamespace checks if a selector belongs to a namespace, of any type if TypeNone is given
+func (selector *Selector) InNamespace(namespaceType int, namespace string) bool {
+	if selector.NamespaceType == TypeNone || selector.Namespace != namespace {
+		return false
+	}
+	return namespaceType == TypeNone || selector.NamespaceType == namespaceType
+}
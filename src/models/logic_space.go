@@ -34,7 +34,10 @@ func (space *Space) CommandAdd(command *Command, overwrite bool) error {
 	return nil
 }
 
-func (space *Space) CommandEdit(command *Command, previousLabel string) error {
+// CommandEdit validates and timestamps the changes done to a command, previous being the command as it
+// was before editing it. The edition is recorded as a new revision of the command by author
+func (space *Space) CommandEdit(command *Command, previous Command, author string) error {
+	previousLabel := previous.Label
 	if command.Label != previousLabel {
 		newLabel := command.Label
 		command.Label = previousLabel
@@ -48,9 +51,28 @@ func (space *Space) CommandEdit(command *Command, previousLabel string) error {
 	command.UpdatedAt = now
 	space.UpdatedAt = now
 
+	command.revise(&previous, author, now)
+
 	return nil
 }
 
+// CommandRevert restores the content of a previous revision of a command, recording it as a new revision
+func (space *Space) CommandRevert(command *Command, number int, author string) error {
+	revision, err := command.RevisionFind(number)
+	if err != nil {
+		return fmt.Errorf("revert command: %v", err)
+	}
+
+	previous := *command
+	previous.Tags = append([]string{}, command.Tags...)
+
+	command.Code = revision.Code
+	command.Description = revision.Description
+	command.Tags = append([]string{}, revision.Tags...)
+
+	return space.CommandEdit(command, previous, author)
+}
+
 func (space *Space) CommandList(item string) []*Command {
 	if item == "" {
 		return space.Entries
@@ -124,19 +146,26 @@ func (space *Space) TagsList(filterTag string) []string {
 	return result
 }
 
+// SearchCommands returns the commands matching a query (see ParseQuery), most relevant first
 func (space *Space) SearchCommands(tag string, criteria string) ([]*Command, error) {
 	if criteria == "" {
 		return nil, fmt.Errorf("could not search with empty criteria")
 	}
 
-	var results []*Command
+	query, err := ParseQuery(criteria)
+	if err != nil {
+		return nil, err
+	}
+	return space.Search(tag, query), nil
+}
+
+// Search returns the commands found by a matcher (a query or a regular expression), most relevant first
+func (space *Space) Search(tag string, matcher Matcher) []*Command {
+	var candidates []*Command
 	for _, command := range space.Entries {
-		if tag != "" && !command.Tagged(tag) {
-			continue
-		}
-		if command.Matches(criteria) {
-			results = append(results, command)
+		if tag == "" || command.Tagged(tag) {
+			candidates = append(candidates, command)
 		}
 	}
-	return results, nil
+	return Rank(matcher, candidates)
 }
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	SyncAdded   = "added"
+	SyncChanged = "changed"
+	SyncDeleted = "deleted"
+)
+
+// SyncChange is a command added, changed or deleted on one side since the last sync. Command is the
+// command as it is now on that side (nil if deleted)
+type SyncChange struct {
+	Label   string
+	Kind    string
+	Command *Command
+}
+
+// SyncConflict is a command changed on both sides since the last sync in ways that could not be merged,
+// and how it was resolved (MergeMine, MergeTheirs or MergeEdited; empty while pending)
+type SyncConflict struct {
+	Label      string
+	Merge      *CommandMerge
+	Resolution string
+}
+
+// SyncPlan are the changes to apply on each side to get both of them in sync, the commands changed on
+// both sides whose changes were merged, and the ones that need to be resolved by hand
+type SyncPlan struct {
+	Push      []*SyncChange
+	Pull      []*SyncChange
+	Merged    []*CommandMerge
+	Conflicts []*SyncConflict
+}
+
+// Empty tells if both sides are already in sync
+func (plan *SyncPlan) Empty() bool {
+	for _, conflict := range plan.Conflicts {
+		if conflict.Resolution == "" {
+			return false
+		}
+	}
+	return len(plan.Push) == 0 && len(plan.Pull) == 0
+}
+
+// Resolve records how a conflict was resolved, pushing and pulling the command resolved wherever it
+// differs from the version there
+func (plan *SyncPlan) Resolve(conflict *SyncConflict, resolution string, command *Command) {
+	conflict.Resolution = resolution
+	plan.applyMerged(command, conflict.Merge)
+}
+
+func (plan *SyncPlan) applyMerged(command *Command, merge *CommandMerge) {
+	if !SameContent(command, merge.Theirs) {
+		plan.Push = append(plan.Push, &SyncChange{Label: merge.Label, Kind: SyncChanged, Command: command})
+	}
+	if !SameContent(command, merge.Mine) {
+		plan.Pull = append(plan.Pull, &SyncChange{Label: merge.Label, Kind: SyncChanged, Command: command})
+	}
+}
+
+// PlanSync compares the commands of a local space and of its cloud counterpart with the ones they had
+// when last synced (none if never synced), using when each command was last updated to tell which ones
+// were added, changed or deleted on each side. Commands changed on both sides are merged field by field,
+// leaving as conflicts the ones where the same field was changed differently
+func PlanSync(state *SyncState, local []*Command, remote []*Command) *SyncPlan {
+	base := []*Command{}
+	if state != nil {
+		base = state.Commands
+	}
+
+	localCommands := commandsByLabel(local)
+	remoteCommands := commandsByLabel(remote)
+	localChanges := syncChanges(base, local)
+	remoteChanges := syncChanges(base, remote)
+
+	baseCommands := commandsByLabel(base)
+
+	plan := SyncPlan{Push: []*SyncChange{}, Pull: []*SyncChange{}, Merged: []*CommandMerge{}, Conflicts: []*SyncConflict{}}
+
+	for _, label := range syncLabels(localChanges, remoteChanges) {
+		mine, changedLocally := localChanges[label]
+		theirs, changedRemotely := remoteChanges[label]
+
+		switch {
+		case changedLocally && !changedRemotely:
+			if !SameContent(mine.Command, remoteCommands[label]) {
+				plan.Push = append(plan.Push, mine)
+			}
+
+		case changedRemotely && !changedLocally:
+			if !SameContent(theirs.Command, localCommands[label]) {
+				plan.Pull = append(plan.Pull, theirs)
+			}
+
+		case mine.Kind == SyncDeleted && theirs.Kind == SyncDeleted, SameContent(mine.Command, theirs.Command):
+			// same change done on both sides
+
+		case mine.Kind == SyncDeleted:
+			// changes win over deletions, so nothing is lost
+			plan.Pull = append(plan.Pull, &SyncChange{Label: label, Kind: SyncAdded, Command: theirs.Command})
+
+		case theirs.Kind == SyncDeleted:
+			plan.Push = append(plan.Push, &SyncChange{Label: label, Kind: SyncAdded, Command: mine.Command})
+
+		default:
+			merge := MergeCommand(baseCommands[label], mine.Command, theirs.Command)
+			if merge.Resolved() {
+				plan.Merged = append(plan.Merged, merge)
+				plan.applyMerged(merge.Merged, merge)
+			} else {
+				plan.Conflicts = append(plan.Conflicts, &SyncConflict{Label: label, Merge: merge})
+			}
+		}
+	}
+
+	return &plan
+}
+
+// syncChanges returns the commands added, changed or deleted since base, by label
+func syncChanges(base []*Command, current []*Command) map[string]*SyncChange {
+	baseCommands := commandsByLabel(base)
+	currentCommands := commandsByLabel(current)
+
+	changes := map[string]*SyncChange{}
+	for label, command := range currentCommands {
+		previous, found := baseCommands[label]
+		if !found {
+			changes[label] = &SyncChange{Label: label, Kind: SyncAdded, Command: command}
+		} else if !command.UpdatedAt.Equal(previous.UpdatedAt) && !SameContent(command, previous) {
+			changes[label] = &SyncChange{Label: label, Kind: SyncChanged, Command: command}
+		}
+	}
+	for label := range baseCommands {
+		if _, found := currentCommands[label]; !found {
+			changes[label] = &SyncChange{Label: label, Kind: SyncDeleted}
+		}
+	}
+	return changes
+}
+
+func syncLabels(changes ...map[string]*SyncChange) []string {
+	labels := []string{}
+	found := map[string]bool{}
+	for _, c := range changes {
+		for label := range c {
+			if !found[label] {
+				found[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func commandsByLabel(commands []*Command) map[string]*Command {
+	byLabel := map[string]*Command{}
+	for _, command := range commands {
+		byLabel[command.Label] = command
+	}
+	return byLabel
+}
+
+// SameContent checks if two commands hold the same label, code, description, URL and tags (nil commands
+// are only the same as other nil ones)
+func SameContent(a *Command, b *Command) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Label == b.Label && a.Code == b.Code && a.Description == b.Description && a.URL == b.URL &&
+		strings.Join(a.Tags, " ") == strings.Join(b.Tags, " ")
+}
@@ -0,0 +1,132 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+func syncCommand(label string, code string, updatedAt int64) *models.Command {
+	return &models.Command{
+		Meta:  models.Meta{UpdatedAt: models.UnixTime(time.Unix(updatedAt, 0))},
+		Label: label,
+		Code:  code,
+	}
+}
+
+func syncLabels(changes []*models.SyncChange) map[string]string {
+	labels := map[string]string{}
+	for _, change := range changes {
+		labels[change.Label] = change.Kind
+	}
+	return labels
+}
+
+func assertSyncChanges(t *testing.T, side string, changes []*models.SyncChange, expected map[string]string) {
+	actual := syncLabels(changes)
+	if len(actual) != len(expected) {
+		t.Errorf("%s: expected %v, got %v", side, expected, actual)
+		return
+	}
+	for label, kind := range expected {
+		if actual[label] != kind {
+			t.Errorf("%s: expected %s to be %s, got %v", side, label, kind, actual)
+		}
+	}
+}
+
+func TestPlanSync(t *testing.T) {
+	state := &models.SyncState{Commands: []*models.Command{
+		syncCommand("unchanged", "ls", 10),
+		syncCommand("edited-locally", "ps", 10),
+		syncCommand("edited-remotely", "df", 10),
+		syncCommand("deleted-locally", "du", 10),
+		syncCommand("deleted-remotely", "top", 10),
+		syncCommand("deleted-edited", "free", 10),
+	}}
+	local := []*models.Command{
+		syncCommand("unchanged", "ls", 10),
+		syncCommand("edited-locally", "ps aux", 20),
+		syncCommand("edited-remotely", "df", 10),
+		syncCommand("deleted-remotely", "top", 10),
+		syncCommand("added-locally", "uptime", 20),
+	}
+	remote := []*models.Command{
+		syncCommand("unchanged", "ls", 10),
+		syncCommand("edited-locally", "ps", 10),
+		syncCommand("edited-remotely", "df -h", 20),
+		syncCommand("deleted-locally", "du", 10),
+		syncCommand("deleted-edited", "free -m", 20),
+		syncCommand("added-remotely", "whoami", 20),
+	}
+
+	plan := models.PlanSync(state, local, remote)
+
+	assertSyncChanges(t, "push", plan.Push, map[string]string{
+		"edited-locally":  models.SyncChanged,
+		"deleted-locally": models.SyncDeleted,
+		"added-locally":   models.SyncAdded,
+	})
+	assertSyncChanges(t, "pull", plan.Pull, map[string]string{
+		"edited-remotely":  models.SyncChanged,
+		"deleted-remotely": models.SyncDeleted,
+		"added-remotely":   models.SyncAdded,
+		"deleted-edited":   models.SyncAdded,
+	})
+	if len(plan.Conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", plan.Conflicts)
+	}
+}
+
+func TestPlanSyncConflicts(t *testing.T) {
+	state := &models.SyncState{Commands: []*models.Command{syncCommand("both", "ls", 10)}}
+
+	plan := models.PlanSync(state, []*models.Command{syncCommand("both", "ls -l", 30)}, []*models.Command{syncCommand("both", "ls -a", 20)})
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Resolution != "" || len(plan.Push) != 0 || len(plan.Pull) != 0 || plan.Empty() {
+		t.Fatalf("code changed on both sides should be a pending conflict: %+v", plan)
+	}
+
+	conflict := plan.Conflicts[0]
+	plan.Resolve(conflict, models.MergeTheirs, conflict.Merge.Resolve(models.MergeTheirs))
+	if conflict.Resolution != models.MergeTheirs || len(plan.Push) != 0 || len(plan.Pull) != 1 || plan.Pull[0].Command.Code != "ls -a" {
+		t.Errorf("keeping their version should only pull it: %+v", plan)
+	}
+
+	plan = models.PlanSync(state, []*models.Command{syncCommand("both", "ls -l", 20)}, []*models.Command{syncCommand("both", "ls -l", 30)})
+	if !plan.Empty() || len(plan.Conflicts) != 0 {
+		t.Errorf("same change done on both sides should not conflict: %+v", plan)
+	}
+}
+
+func TestPlanSyncMerges(t *testing.T) {
+	base := syncCommand("both", "ls", 10)
+	base.Tags = []string{"files"}
+	mine := syncCommand("both", "ls -l", 20)
+	mine.Tags = []string{"files", "mine"}
+	theirs := syncCommand("both", "ls", 30)
+	theirs.Description = "List files"
+	theirs.Tags = []string{"files"}
+
+	plan := models.PlanSync(&models.SyncState{Commands: []*models.Command{base}}, []*models.Command{mine}, []*models.Command{theirs})
+	if len(plan.Conflicts) != 0 || len(plan.Merged) != 1 || len(plan.Push) != 1 || len(plan.Pull) != 1 {
+		t.Fatalf("changes to different fields should be merged and applied on both sides: %+v", plan)
+	}
+
+	merged := plan.Push[0].Command
+	if merged.Code != "ls -l" || merged.Description != "List files" || strings.Join(merged.Tags, ",") != "files,mine" {
+		t.Errorf("unexpected merged command: %+v", merged)
+	}
+}
+
+func TestPlanFirstSync(t *testing.T) {
+	plan := models.PlanSync(nil,
+		[]*models.Command{syncCommand("shared", "ls", 10), syncCommand("mine", "ps", 10)},
+		[]*models.Command{syncCommand("shared", "ls", 20), syncCommand("theirs", "df", 10)},
+	)
+
+	if len(plan.Push) != 1 || plan.Push[0].Label != "mine" || len(plan.Pull) != 1 || plan.Pull[0].Label != "theirs" {
+		t.Errorf("unexpected first sync: push %v, pull %v", syncLabels(plan.Push), syncLabels(plan.Pull))
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import "fmt"
+
+const (
+	TrashKindSpace   = "space"
+	TrashKindCommand = "command"
+)
+
+func NewSpaceTrashEntry(space *Space) *TrashEntry {
+	space.ID = space.Selector.String()
+	return &TrashEntry{
+		Selector:  space.Selector,
+		DeletedAt: UnixTimeNow(),
+		Space:     space,
+	}
+}
+
+func NewCommandTrashEntry(command *Command) *TrashEntry {
+	command.ID = command.Selector.String()
+	return &TrashEntry{
+		Selector:  command.Selector,
+		DeletedAt: UnixTimeNow(),
+		Command:   command,
+	}
+}
+
+func (entry *TrashEntry) Kind() string {
+	if entry.Command != nil {
+		return TrashKindCommand
+	}
+	return TrashKindSpace
+}
+
+// ParseSelectors rebuilds the selectors of the deleted content, not stored in the trash files
+func (entry *TrashEntry) ParseSelectors() error {
+	var err error
+	if entry.Command != nil {
+		entry.Command.Selector, err = ParseSelectorMandatoryItem(entry.Command.ID)
+		if err != nil {
+			return fmt.Errorf("command's ID (%s) is not a valid selector: %v", entry.Command.ID, err)
+		}
+		entry.Selector = entry.Command.Selector
+		return nil
+	}
+
+	if entry.Space == nil {
+		return fmt.Errorf("trash entry with no content")
+	}
+
+	entry.Space.Selector, err = ParseSelector(entry.Space.ID)
+	if err != nil {
+		return fmt.Errorf("space's ID (%s) is not a valid selector: %v", entry.Space.ID, err)
+	}
+	for _, command := range entry.Space.Entries {
+		command.Selector, err = ParseSelectorMandatoryItem(command.ID)
+		if err != nil {
+			return fmt.Errorf("command's ID (%s) is not a valid selector: %v", command.ID, err)
+		}
+	}
+	entry.Selector = entry.Space.Selector
+	return nil
+}
+
+// Matches tells if the trash entry holds the content referenced by a selector: a command for
+// selectors with an item, a whole space otherwise
+func (entry *TrashEntry) Matches(selector *Selector) bool {
+	if selector.Item != "" && entry.Kind() != TrashKindCommand || selector.Item == "" && entry.Kind() != TrashKindSpace {
+		return false
+	}
+	return entry.Selector.String() == selector.String()
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholders look like {{name}} or {{name:default value}}
+var variableRegexp = regexp.MustCompile(`{{\s*([a-zA-Z0-9_-]+)\s*(:([^}]*))?}}`)
+
+func (variable *Variable) String() string {
+	if variable.HasDefault {
+		return fmt.Sprintf("%s (default: %s)", variable.Name, variable.Default)
+	}
+	return variable.Name
+}
+
+// Variables returns the placeholders found in the command's code, in order of appearance and without duplicates
+func (command *Command) Variables() []Variable {
+	variables := []Variable{}
+	found := make(map[string]bool)
+
+	for _, match := range variableRegexp.FindAllStringSubmatch(command.Code, -1) {
+		name := match[1]
+		if found[name] {
+			continue
+		}
+		found[name] = true
+
+		variables = append(variables, Variable{
+			Name:       name,
+			Default:    strings.TrimSpace(match[3]),
+			HasDefault: match[2] != "",
+		})
+	}
+
+	return variables
+}
+
+// Placeholder returns how the variable is written in the code of a command
+func (variable *Variable) Placeholder() string {
+	if variable.HasDefault {
+		return fmt.Sprintf("{{%s:%s}}", variable.Name, variable.Default)
+	}
+	return fmt.Sprintf("{{%s}}", variable.Name)
+}
+
+// ReplaceVariables rewrites every placeholder found in the command's code with the result of replace
+func (command *Command) ReplaceVariables(replace func(variable Variable) string) string {
+	return variableRegexp.ReplaceAllStringFunc(command.Code, func(placeholder string) string {
+		match := variableRegexp.FindStringSubmatch(placeholder)
+		return replace(Variable{
+			Name:       match[1],
+			Default:    strings.TrimSpace(match[3]),
+			HasDefault: match[2] != "",
+		})
+	})
+}
+
+// ResolveCode replaces the placeholders in the command's code with the values provided, falling back to their defaults
+func (command *Command) ResolveCode(values map[string]string) (string, error) {
+	missing := []string{}
+
+	code := variableRegexp.ReplaceAllStringFunc(command.Code, func(placeholder string) string {
+		match := variableRegexp.FindStringSubmatch(placeholder)
+		if value, ok := values[match[1]]; ok {
+			return value
+		}
+		if match[2] != "" {
+			return strings.TrimSpace(match[3])
+		}
+		missing = append(missing, match[1])
+		return placeholder
+	})
+
+	if len(missing) != 0 {
+		return "", fmt.Errorf("resolve code: no value provided for variables: %s", strings.Join(missing, ", "))
+	}
+
+	return code, nil
+}
+
+// ParseVariableValues converts a list of 'name=value' assignments into a map
+func ParseVariableValues(assignments []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, assignment := range assignments {
+		parts := strings.SplitN(assignment, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid variable assignment '%s' (expected name=value)", assignment)
+		}
+		values[parts[0]] = parts[1]
+	}
+	return values, nil
+}
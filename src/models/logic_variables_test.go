@@ -0,0 +1,62 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+func TestCommandVariables(t *testing.T) {
+	command := models.Command{
+		Code: "ssh {{user:root}}@{{host}} -p {{ port:22 }} && echo {{host}}",
+	}
+
+	variables := command.Variables()
+
+	if len(variables) != 3 {
+		t.Fatalf("expected 3 variables but got %d: %v", len(variables), variables)
+	}
+	if variables[0].Name != "user" || !variables[0].HasDefault || variables[0].Default != "root" {
+		t.Errorf("variable 'user' not parsed properly: %v", variables[0])
+	}
+	if variables[1].Name != "host" || variables[1].HasDefault {
+		t.Errorf("variable 'host' not parsed properly: %v", variables[1])
+	}
+	if variables[2].Name != "port" || variables[2].Default != "22" {
+		t.Errorf("variable 'port' not parsed properly: %v", variables[2])
+	}
+}
+
+func TestCommandResolveCode(t *testing.T) {
+	command := models.Command{
+		Code: "ssh {{user:root}}@{{host}} -p {{port:22}}",
+	}
+
+	code, err := command.ResolveCode(map[string]string{"host": "example.com", "port": "2222"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "ssh root@example.com -p 2222" {
+		t.Errorf("code not resolved properly: %s", code)
+	}
+
+	_, err = command.ResolveCode(map[string]string{})
+	if err == nil {
+		t.Error("expected error for missing variable was not created")
+	}
+}
+
+func TestParseVariableValues(t *testing.T) {
+	values, err := models.ParseVariableValues([]string{"host=example.com", "query=a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["host"] != "example.com" || values["query"] != "a=b" {
+		t.Errorf("variable values not parsed properly: %v", values)
+	}
+
+	_, err = models.ParseVariableValues([]string{"host"})
+	if err == nil {
+		t.Error("expected error for invalid assignment was not created")
+	}
+}
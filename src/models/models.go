@@ -43,11 +43,45 @@ type Space struct {
 
 type Command struct {
 	Meta
-	Label       string   `json:"label"`
+	Label       string     `json:"label"`
+	Code        string     `json:"code"`
+	Description string     `json:"description"`
+	URL         string     `json:"url" dynamodbav:",omitempty"`
+	Tags        []string   `json:"tags" dynamodbav:",omitempty"`
+	Revisions   []Revision `json:"revisions,omitempty" dynamodbav:"-"`
+}
+
+type Revision struct {
+	Number      int      `json:"revision"`
 	Code        string   `json:"code"`
 	Description string   `json:"description"`
-	URL         string   `json:"url" dynamodbav:",omitempty"`
-	Tags        []string `json:"tags" dynamodbav:",omitempty"`
+	Tags        []string `json:"tags"`
+	Author      string   `json:"author,omitempty"`
+	CreatedAt   UnixTime `json:"created-at"`
+}
+
+type TrashEntry struct {
+	ID        string    `json:"-"`
+	Selector  *Selector `json:"-"`
+	DeletedAt UnixTime  `json:"deleted-at"`
+	Space     *Space    `json:"space,omitempty"`
+	Command   *Command  `json:"command,omitempty"`
+}
+
+// SyncState records which cloud space a local space is synced with, and the commands both had after
+// the last sync. Syncs counts the syncs done from this cbox (the cloud doesn't version spaces)
+type SyncState struct {
+	Space    string     `json:"space"`
+	Remote   string     `json:"remote"`
+	Syncs    int        `json:"syncs"`
+	SyncedAt UnixTime   `json:"synced-at"`
+	Commands []*Command `json:"commands"`
+}
+
+type Variable struct {
+	Name       string
+	Default    string
+	HasDefault bool
 }
 
 type Cloud struct {
@@ -61,4 +95,25 @@ type Cloud struct {
 	BaseURL     *url.URL
 	HttpClient  *http.Client
 	Cbox        *CBox
+	Cache       CloudCache
+	CacheTTL    time.Duration
+	Offline     bool
+	Retries     int
+	RetryWait   time.Duration
+}
+
+// CloudCache keeps the responses of the cloud by selector, so they can be reused and revalidated instead
+// of fetched again, or used while offline
+type CloudCache interface {
+	CloudResponseFind(kind string, selector *Selector) (*CloudResponse, error)
+	CloudResponseStore(kind string, selector *Selector, response *CloudResponse) error
+	CloudResponsesDelete(space *Selector) error
+}
+
+// CloudResponse is a response of the cloud as it was last fetched
+type CloudResponse struct {
+	Body         string   `json:"body"`
+	ETag         string   `json:"etag"`
+	LastModified string   `json:"last-modified"`
+	FetchedAt    UnixTime `json:"fetched-at"`
 }
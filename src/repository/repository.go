@@ -5,15 +5,20 @@ import (
 	"path"
 
 	"github.com/dplabs/cbox/src/tools"
+	"github.com/gofrs/flock"
 	homedir "github.com/mitchellh/go-homedir"
 )
 
 type Repository struct {
-	Path string
+	Path      string
+	store     Store
+	lock      *flock.Flock
+	snapshots map[string][]byte
 }
 
 const (
-	cboxDir = ".cbox"
+	cboxDir  = ".cbox"
+	lockFile = ".lock"
 )
 
 func InitRepository(repoPath string) *Repository {
@@ -30,10 +35,19 @@ func InitRepository(repoPath string) *Repository {
 	tools.CreateDirectoryIfNotExists(repoPath)
 
 	repo := Repository{
-		Path: repoPath,
+		Path:      repoPath,
+		lock:      flock.New(path.Join(repoPath, lockFile)),
+		snapshots: make(map[string][]byte),
 	}
 
-	repo.loadSettings()
+	// the storage backend is one of the settings, so they are read from config.yml to pick the store
+	config := configFile{dir: repo.Path}
+	repo.loadSettings(&config)
+
+	repo.store = newStore(repo.GetStorageBackend(), repo.Path)
+	if !config.usedBy(repo.store) {
+		repo.loadSettings(repo.store)
+	}
 
 	return &repo
 }
@@ -41,3 +55,23 @@ func InitRepository(repoPath string) *Repository {
 func (repo *Repository) resolve(paths ...string) string {
 	return path.Join(repo.Path, path.Join(paths...))
 }
+
+// readLock prevents other cbox processes from writing while the repository is being read
+func (repo *Repository) readLock() {
+	if err := repo.lock.RLock(); err != nil {
+		log.Fatalf("repository: could not lock '%s': %v", repo.Path, err)
+	}
+}
+
+// writeLock grants exclusive access to the repository to the current process
+func (repo *Repository) writeLock() {
+	if err := repo.lock.Lock(); err != nil {
+		log.Fatalf("repository: could not lock '%s': %v", repo.Path, err)
+	}
+}
+
+func (repo *Repository) unlock() {
+	if err := repo.lock.Unlock(); err != nil {
+		log.Fatalf("repository: could not unlock '%s': %v", repo.Path, err)
+	}
+}
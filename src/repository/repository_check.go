@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+// Checker is implemented by stores able to validate their content, reporting every problem found
+type Checker interface {
+	Check() []error
+}
+
+func (repo *Repository) Check() []error {
+	repo.readLock()
+	defer repo.unlock()
+
+	if checker, ok := repo.store.(Checker); ok {
+		return checker.Check()
+	}
+	return []error{}
+}
+
+// checkSpace validates the consistency of a space loaded from a store, given the selector it was stored under
+func checkSpace(space *models.Space, expected *models.Selector) []error {
+	problems := []error{}
+
+	if space.Selector.NamespaceType != expected.NamespaceType || space.Selector.Namespace != expected.Namespace || space.Selector.Space != expected.Space {
+		problems = append(problems, fmt.Errorf("space ID '%s' does not match where it's stored ('%s')", space.ID, expected.String()))
+	}
+	if space.Label != space.Selector.Space {
+		problems = append(problems, fmt.Errorf("space label '%s' does not match its ID '%s'", space.Label, space.ID))
+	}
+
+	labels := make(map[string]bool)
+	for _, command := range space.Entries {
+		if labels[command.Label] {
+			problems = append(problems, fmt.Errorf("duplicated command label '%s'", command.Label))
+		}
+		labels[command.Label] = true
+
+		if command.Label != command.Selector.Item {
+			problems = append(problems, fmt.Errorf("command label '%s' does not match its ID '%s'", command.Label, command.ID))
+		}
+		if command.Selector.NamespaceType != space.Selector.NamespaceType || command.Selector.Namespace != space.Selector.Namespace || command.Selector.Space != space.Selector.Space {
+			problems = append(problems, fmt.Errorf("command ID '%s' does not belong to space '%s'", command.ID, space.ID))
+		}
+		if command.Code == "" {
+			problems = append(problems, fmt.Errorf("command '%s' has no code", command.Label))
+		}
+	}
+
+	return problems
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dplabs/cbox/src/tools/console"
 
 	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
 
 	"github.com/spf13/viper"
 )
@@ -41,6 +43,27 @@ var (
 	cloudSettingsJWT       string
 )
 
+// CloudCacheTTL returns for how long responses of the cloud are used without revalidating them
+func (repo *Repository) CloudCacheTTL() time.Duration {
+	return viper.GetDuration("cbox.cloud.cache-ttl")
+}
+
+// CloudHTTPOptions returns how to connect to the cloud: timeouts, proxy and extra CAs to trust
+func (repo *Repository) CloudHTTPOptions() tools.HTTPOptions {
+	return tools.HTTPOptions{
+		ConnectTimeout: viper.GetDuration("cbox.cloud.connect-timeout"),
+		ReadTimeout:    viper.GetDuration("cbox.cloud.read-timeout"),
+		Proxy:          viper.GetString("cbox.cloud.proxy"),
+		CAFile:         viper.GetString("cbox.cloud.ca-file"),
+	}
+}
+
+// CloudRetries returns how many times failed requests to the cloud are retried, and how long to wait
+// before the first retry (doubled for each of the next ones)
+func (repo *Repository) CloudRetries() (int, time.Duration) {
+	return viper.GetInt("cbox.cloud.retries"), viper.GetDuration("cbox.cloud.retry-wait")
+}
+
 func (repo *Repository) LoadCloudSettings() (string, string, string, string, string, string) {
 
 	env := repo.GetEnv()
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+)
+
+const (
+	pathCache          = "cache"
+	pathCloudCache     = "cache/spaces"
+	pathCloudResponses = "cache/responses"
+)
+
+// CachedSpace is the content of a cloud space as it was last fetched
+type CachedSpace struct {
+	CachedAt models.UnixTime `json:"cached-at"`
+	*models.Space
+}
+
+// CloudCacheStore keeps a copy of the commands of a cloud space, so they can be searched later without
+// reaching the cloud
+func (repo *Repository) CloudCacheStore(space *models.Space) error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	tools.CreateDirectoryIfNotExists(repo.resolve(pathCache))
+	tools.CreateDirectoryIfNotExists(repo.resolve(pathCloudCache))
+
+	raw, err := json.MarshalIndent(&CachedSpace{CachedAt: models.UnixTimeNow(), Space: space}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cloud cache: could not generate JSON: %v", err)
+	}
+
+	if err := tools.WriteFileAtomic(repo.cloudCacheFile(space.Selector), raw, 0644); err != nil {
+		return fmt.Errorf("cloud cache: could not write file: %v", err)
+	}
+	return nil
+}
+
+// CloudCacheList returns the cloud spaces cached
+func (repo *Repository) CloudCacheList() ([]*CachedSpace, error) {
+	repo.readLock()
+	defer repo.unlock()
+
+	files, err := ioutil.ReadDir(repo.resolve(pathCloudCache))
+	if os.IsNotExist(err) {
+		return []*CachedSpace{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cloud cache: could not read cache: %v", err)
+	}
+
+	spaces := []*CachedSpace{}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(repo.resolve(pathCloudCache, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("cloud cache: could not read file '%s': %v", f.Name(), err)
+		}
+
+		cached := CachedSpace{Space: &models.Space{}}
+		if err := json.Unmarshal(data, &cached); err != nil {
+			return nil, fmt.Errorf("cloud cache: could not parse file '%s': %v", f.Name(), err)
+		}
+
+		if cached.Selector, err = models.ParseSelector(cached.ID); err != nil {
+			return nil, fmt.Errorf("cloud cache: file '%s': %v", f.Name(), err)
+		}
+		for _, command := range cached.Entries {
+			if command.Selector, err = models.ParseSelectorMandatoryItem(command.ID); err != nil {
+				return nil, fmt.Errorf("cloud cache: file '%s': %v", f.Name(), err)
+			}
+		}
+
+		spaces = append(spaces, &cached)
+	}
+
+	return spaces, nil
+}
+
+// CloudResponseFind returns the response of the cloud last fetched about a selector (nil if not cached)
+func (repo *Repository) CloudResponseFind(kind string, selector *models.Selector) (*models.CloudResponse, error) {
+	repo.readLock()
+	defer repo.unlock()
+
+	data, err := ioutil.ReadFile(repo.cloudResponseFile(kind, selector))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cloud cache: could not read file: %v", err)
+	}
+
+	var response models.CloudResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("cloud cache: could not parse file: %v", err)
+	}
+	return &response, nil
+}
+
+// CloudResponseStore keeps a response of the cloud about a selector
+func (repo *Repository) CloudResponseStore(kind string, selector *models.Selector, response *models.CloudResponse) error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	tools.CreateDirectoryIfNotExists(repo.resolve(pathCache))
+	tools.CreateDirectoryIfNotExists(repo.resolve(pathCloudResponses))
+	tools.CreateDirectoryIfNotExists(repo.resolve(pathCloudResponses, cloudCacheName(selector)))
+
+	raw, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cloud cache: could not generate JSON: %v", err)
+	}
+
+	if err := tools.WriteFileAtomic(repo.cloudResponseFile(kind, selector), raw, 0644); err != nil {
+		return fmt.Errorf("cloud cache: could not write file: %v", err)
+	}
+	return nil
+}
+
+// CloudResponsesDelete forgets every response of the cloud kept about a space (and its commands)
+func (repo *Repository) CloudResponsesDelete(space *models.Selector) error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	if err := os.RemoveAll(repo.resolve(pathCloudResponses, cloudCacheName(space))); err != nil {
+		return fmt.Errorf("cloud cache: could not delete responses: %v", err)
+	}
+	return nil
+}
+
+func (repo *Repository) cloudCacheFile(selector *models.Selector) string {
+	return repo.resolve(pathCloudCache, cloudCacheName(selector)+".json")
+}
+
+// cloudResponseFile returns where responses are kept: a directory per space, holding a file per kind of
+// response about the whole space or any of its items
+func (repo *Repository) cloudResponseFile(kind string, selector *models.Selector) string {
+	filename := kind
+	if selector.Item != "" {
+		filename = kind + "-" + selector.Item
+	}
+	return repo.resolve(pathCloudResponses, cloudCacheName(selector), filename+".json")
+}
+
+// cloudCacheName returns the name of the files cached for a cloud space
+func cloudCacheName(selector *models.Selector) string {
+	filename := strings.NewReplacer("/", filenameSeparatorOrganization).Replace(selector.CloneForItem("").String())
+	return strings.TrimPrefix(filename, "@")
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/console"
+)
+
+func spaceSnapshot(space *models.Space) []byte {
+	raw, err := json.Marshal(space)
+	if err != nil {
+		log.Fatalf("repository: snapshot space '%s': %v", space.String(), err)
+	}
+	return raw
+}
+
+func parseSnapshot(raw []byte) *models.Space {
+	var space models.Space
+	if err := json.Unmarshal(raw, &space); err != nil {
+		log.Fatalf("repository: parse snapshot: %v", err)
+	}
+	return &space
+}
+
+func commandChanged(a *models.Command, b *models.Command) bool {
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	return errA != nil || errB != nil || !bytes.Equal(rawA, rawB)
+}
+
+func commandsByLabel(space *models.Space) map[string]*models.Command {
+	commands := make(map[string]*models.Command)
+	for _, command := range space.Entries {
+		commands[command.Label] = command
+	}
+	return commands
+}
+
+// mergeSpace applies the changes done in mine since base was loaded over stored (the space as
+// currently persisted by someone else), leaving the result in mine. Changes are merged per command:
+// commands changed only on one side take that side's version, and edits win over deletions. Commands
+// edited on both sides are merged field by field, and if some field was changed differently on each side
+// the stored version is kept too, renamed, so none of the edits is lost
+func mergeSpace(base *models.Space, mine *models.Space, stored *models.Space) {
+	baseCommands := commandsByLabel(base)
+	mineCommands := commandsByLabel(mine)
+	storedCommands := commandsByLabel(stored)
+
+	merged := []*models.Command{}
+	theirs := []*models.Command{}
+
+	for _, command := range stored.Entries {
+		baseCommand, inBase := baseCommands[command.Label]
+		mineCommand, inMine := mineCommands[command.Label]
+		changedByThem := !inBase || commandChanged(baseCommand, command)
+
+		if !inMine {
+			if inBase && !changedByThem {
+				// deleted by us
+				continue
+			}
+			merged = append(merged, command)
+			continue
+		}
+
+		changedByUs := !inBase || commandChanged(baseCommand, mineCommand)
+		switch {
+		case !changedByUs:
+			merged = append(merged, command)
+		case changedByThem && commandChanged(mineCommand, command):
+			merge := models.MergeCommand(baseCommand, mineCommand, command)
+			merged = append(merged, merge.Resolve(models.MergeMine))
+			if !merge.Resolved() {
+				theirs = append(theirs, command)
+			}
+		default:
+			merged = append(merged, mineCommand)
+		}
+	}
+
+	for _, command := range mine.Entries {
+		if _, inStored := storedCommands[command.Label]; inStored {
+			continue
+		}
+		if baseCommand, inBase := baseCommands[command.Label]; inBase && !commandChanged(baseCommand, command) {
+			// deleted by someone else and not modified by us
+			continue
+		}
+		merged = append(merged, command)
+	}
+
+	mine.Entries = merged
+
+	for _, command := range theirs {
+		renamed := *command
+		renamed.Label = unusedLabel(mine, command.Label+"-theirs")
+		renamed.Selector = mine.Selector.CloneForItem(renamed.Label)
+		mine.Entries = append(mine.Entries, &renamed)
+
+		console.PrintWarning(fmt.Sprintf("Command '%s' was also changed by a different cbox process, so its version was kept as '%s'\n", command.Label, renamed.Label))
+	}
+
+	if mine.Description == base.Description {
+		mine.Description = stored.Description
+	}
+	if stored.UpdatedAt.After(mine.UpdatedAt) {
+		mine.UpdatedAt = stored.UpdatedAt
+	}
+}
+
+// unusedLabel returns label, or label followed by a number if a command of the space already uses it
+func unusedLabel(space *models.Space, label string) string {
+	commands := commandsByLabel(space)
+	candidate := label
+	for i := 2; commands[candidate] != nil; i++ {
+		candidate = fmt.Sprintf("%s-%d", label, i)
+	}
+	return candidate
+}
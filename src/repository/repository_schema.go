@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const schemaVersionField = "schema-version"
+
+// spaceMigration upgrades the raw JSON representation of a space by a single schema version
+type spaceMigration func(raw map[string]interface{}) error
+
+// spaceMigrations holds, in order, the migrations needed to upgrade a space file to the
+// current schema: the one at position N upgrades a file from version N to N+1
+var spaceMigrations = []spaceMigration{
+	migrateSpaceToV1,
+}
+
+func currentSchemaVersion() int {
+	return len(spaceMigrations)
+}
+
+// migrateSpace upgrades raw to the current schema, returning the version it was originally written with
+func migrateSpace(raw map[string]interface{}) (int, error) {
+	version := 0
+	if value, ok := raw[schemaVersionField]; ok {
+		number, ok := value.(float64)
+		if !ok {
+			return 0, fmt.Errorf("invalid %s: %v", schemaVersionField, value)
+		}
+		version = int(number)
+	}
+
+	if version > currentSchemaVersion() {
+		return version, fmt.Errorf("%s %d is newer than the one supported (%d), please upgrade cbox", schemaVersionField, version, currentSchemaVersion())
+	}
+
+	for v := version; v < currentSchemaVersion(); v++ {
+		if err := spaceMigrations[v](raw); err != nil {
+			return version, fmt.Errorf("migration to %s %d failed: %v", schemaVersionField, v+1, err)
+		}
+	}
+	raw[schemaVersionField] = currentSchemaVersion()
+
+	return version, nil
+}
+
+// v1: first versioned schema, lists stored as null are normalized into empty ones
+func migrateSpaceToV1(raw map[string]interface{}) error {
+	if raw["entries"] == nil {
+		raw["entries"] = []interface{}{}
+	}
+
+	entries, ok := raw["entries"].([]interface{})
+	if !ok {
+		return fmt.Errorf("entries is not a list")
+	}
+
+	for _, entry := range entries {
+		command, ok := entry.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid entry: %v", entry)
+		}
+		if command["tags"] == nil {
+			command["tags"] = []interface{}{}
+		}
+	}
+
+	return nil
+}
+
+func remarshal(raw map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
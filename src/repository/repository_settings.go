@@ -3,6 +3,7 @@ package repository
 import (
 	"log"
 	"os"
+	"path"
 
 	"github.com/dplabs/cbox/src/tools"
 	"github.com/spf13/viper"
@@ -32,21 +33,62 @@ func (repo *Repository) GetEnv() string {
 	return env
 }
 
-func (repo *Repository) loadSettings() {
+func (repo *Repository) GetStorageBackend() string {
+	backend := viper.GetString("cbox.storage.backend")
 
-	env := repo.GetEnv()
+	if os.Getenv("CBOX_STORAGE_BACKEND") != "" {
+		backend = os.Getenv("CBOX_STORAGE_BACKEND")
+	}
 
-	configFile := repo.resolve(configFilePath)
-	tools.CreateFileIfNotExists(configFile)
+	return backend
+}
 
-	viper.AddConfigPath(repo.Path)
-	viper.SetConfigName(configFileName)
+// settingsLoader is implemented by anything able to load the settings (into viper)
+type settingsLoader interface {
+	LoadSettings() error
+}
 
-	if err := viper.ReadInConfig(); err != nil {
+func (repo *Repository) loadSettings(loader settingsLoader) {
+	if err := loader.LoadSettings(); err != nil {
 		log.Fatal(err)
 	}
 
-	defaultSettings(env)
+	defaultSettings(repo.GetEnv())
+}
+
+// SaveSettings stores the current settings
+func (repo *Repository) SaveSettings() error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	return repo.store.PersistSettings()
+}
+
+// configFile keeps the settings in config.yml, within the repository directory
+type configFile struct {
+	dir string
+}
+
+func (config *configFile) file() string {
+	return path.Join(config.dir, configFilePath)
+}
+
+// usedBy tells if a store keeps its settings in this very config file (so they're already loaded)
+func (config *configFile) usedBy(store Store) bool {
+	settings, ok := store.(interface{ file() string })
+	return ok && settings.file() == config.file()
+}
+
+func (config *configFile) LoadSettings() error {
+	file := config.file()
+	tools.CreateFileIfNotExists(file)
+
+	viper.SetConfigFile(file)
+	return viper.ReadInConfig()
+}
+
+func (config *configFile) PersistSettings() error {
+	return viper.WriteConfig()
 }
 
 func defaultSettings(env string) {
@@ -54,4 +96,13 @@ func defaultSettings(env string) {
 	viper.SetDefault("cbox.environment", env)
 	viper.SetDefault("cbox.results.mode", "interactive")
 	viper.SetDefault("cbox.results.sort", "name")
+	viper.SetDefault("cbox.edit.editor", false)
+	viper.SetDefault("cbox.storage.backend", StorageBackendJSON)
+	viper.SetDefault("cbox.cloud.cache-ttl", "10m")
+	viper.SetDefault("cbox.cloud.connect-timeout", "10s")
+	viper.SetDefault("cbox.cloud.read-timeout", "30s")
+	viper.SetDefault("cbox.cloud.retries", 3)
+	viper.SetDefault("cbox.cloud.retry-wait", "500ms")
+	viper.SetDefault("cbox.cloud.proxy", "")
+	viper.SetDefault("cbox.cloud.ca-file", "")
 }
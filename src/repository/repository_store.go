@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/console"
+)
+
+const (
+	StorageBackendJSON   = "json"
+	StorageBackendSQLite = "sqlite"
+)
+
+// ErrSpaceNotFound is returned (wrapped) by stores when the space requested is not stored
+var ErrSpaceNotFound = errors.New("space not found")
+
+// Store is implemented by every storage backend able to keep the spaces and settings of a cbox
+type Store interface {
+	LoadSpaces() ([]*models.Space, bool)
+	LoadSpace(namespaceType int, namespace string, label string) (*models.Space, error)
+	Persist(space *models.Space)
+	Delete(selector *models.Selector)
+	LoadSettings() error
+	PersistSettings() error
+}
+
+// Searcher is implemented by stores able to resolve searches by themselves, without loading every space
+type Searcher interface {
+	SpaceFind(namespaceType int, namespace string, label string) (*models.Space, error)
+	SearchCommands(space *models.Selector, tag string, matcher models.Matcher) ([]*models.Command, error)
+	TagsList(space *models.Selector, tag string) ([]string, error)
+}
+
+// Upgrader is implemented by stores which may load spaces written with older schemas, to rewrite them
+// using the current one
+type Upgrader interface {
+	Outdated() bool
+	Upgrade() error
+}
+
+func StorageBackends() []string {
+	return []string{StorageBackendJSON, StorageBackendSQLite}
+}
+
+func newStore(backend string, path string) Store {
+	switch backend {
+	case StorageBackendJSON:
+		return newJSONStore(path)
+	case StorageBackendSQLite:
+		return newSQLiteStore(path)
+	}
+
+	log.Fatalf("repository: unknown storage backend '%s' (available: %v)", backend, StorageBackends())
+	return nil
+}
+
+func (repo *Repository) LoadSpaces() ([]*models.Space, bool) {
+	repo.readLock()
+	spaces, isNewRepository := repo.store.LoadSpaces()
+	repo.unlock()
+
+	if upgrader, ok := repo.store.(Upgrader); ok && upgrader.Outdated() {
+		repo.writeLock()
+		err := upgrader.Upgrade()
+		repo.unlock()
+		if err != nil {
+			log.Fatalf("repository: %v", err)
+		}
+	}
+
+	for _, space := range spaces {
+		repo.snapshots[space.ID] = spaceSnapshot(space)
+	}
+
+	return spaces, isNewRepository
+}
+
+// Save persists the spaces modified since they were loaded. If any of them was changed meanwhile
+// by a different process, both sets of changes are merged instead of overwriting the stored one
+func (repo *Repository) Save(spaces []*models.Space) {
+	repo.writeLock()
+	defer repo.unlock()
+
+	for _, space := range spaces {
+		normalizeIDs(space)
+
+		snapshot := spaceSnapshot(space)
+		base, loaded := repo.snapshots[space.ID]
+		if loaded && bytes.Equal(base, snapshot) {
+			continue
+		}
+
+		if loaded {
+			stored, err := repo.store.LoadSpace(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+			if errors.Is(err, ErrSpaceNotFound) {
+				// deleted (or trashed) meanwhile, so it's not restored
+				console.PrintWarning(fmt.Sprintf("Space '%s' was deleted by a different cbox process, so your changes to it were not saved\n", space.String()))
+				delete(repo.snapshots, space.ID)
+				continue
+			} else if err != nil {
+				log.Fatalf("repository: store space '%s': %v", space.String(), err)
+			}
+			if !bytes.Equal(base, spaceSnapshot(stored)) {
+				mergeSpace(parseSnapshot(base), space, stored)
+				normalizeIDs(space)
+				snapshot = spaceSnapshot(space)
+			}
+		} else {
+			stored, err := repo.store.LoadSpace(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+			if err == nil {
+				// created meanwhile by a different process too, so both are merged as if they were empty before
+				mergeSpace(&models.Space{}, space, stored)
+				normalizeIDs(space)
+				snapshot = spaceSnapshot(space)
+			} else if !errors.Is(err, ErrSpaceNotFound) {
+				log.Fatalf("repository: store space '%s': %v", space.String(), err)
+			}
+		}
+
+		repo.store.Persist(space)
+		repo.snapshots[space.ID] = snapshot
+	}
+}
+
+func (repo *Repository) Delete(selector *models.Selector) {
+	repo.writeLock()
+	defer repo.unlock()
+
+	repo.store.Delete(selector)
+	delete(repo.snapshots, selector.CloneForItem("").String())
+}
+
+func normalizeIDs(space *models.Space) {
+	space.ID = space.Selector.String()
+
+	for _, command := range space.Entries {
+		command.Selector.NamespaceType = space.Selector.NamespaceType
+		command.Selector.Namespace = space.Selector.Namespace
+		command.Selector.Space = space.Label
+
+		command.ID = command.Selector.String()
+	}
+}
+
+// Searcher returns the current store if it supports searching by itself, nil otherwise
+func (repo *Repository) Searcher() Searcher {
+	if searcher, ok := repo.store.(Searcher); ok {
+		return searcher
+	}
+	return nil
+}
+
+// Migrate copies every space from the current store into a store of a different backend. Spaces the target
+// store kept from a previous migration but not found in the current one are deleted, so they don't come back
+func (repo *Repository) Migrate(backend string) (int, error) {
+	current := repo.GetStorageBackend()
+	if backend == current {
+		return 0, fmt.Errorf("storage already using backend '%s'", backend)
+	}
+
+	target := newStore(backend, repo.Path)
+
+	repo.writeLock()
+	defer repo.unlock()
+
+	spaces, _ := repo.store.LoadSpaces()
+	migrated := map[string]bool{}
+	for _, space := range spaces {
+		migrated[space.Selector.String()] = true
+	}
+
+	leftovers, _ := target.LoadSpaces()
+	for _, space := range leftovers {
+		if !migrated[space.Selector.String()] {
+			target.Delete(space.Selector)
+		}
+	}
+
+	for _, space := range spaces {
+		target.Persist(space)
+	}
+
+	repo.store = target
+
+	return len(spaces), nil
+}
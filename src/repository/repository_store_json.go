@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+)
+
+const (
+	filenameSeparatorUser         = ":"
+	filenameSeparatorOrganization = "="
+
+	pathSpaces = "spaces"
+)
+
+// jsonStore keeps every space in its own JSON file within the 'spaces' directory, and the settings in
+// config.yml
+type jsonStore struct {
+	configFile
+	Path string
+	// space files loaded which were written with an older schema
+	outdated []string
+}
+
+func newJSONStore(path string) *jsonStore {
+	return &jsonStore{
+		configFile: configFile{dir: path},
+		Path:       path,
+	}
+}
+
+func (store *jsonStore) resolve(paths ...string) string {
+	return path.Join(store.Path, path.Join(paths...))
+}
+
+func (store *jsonStore) LoadSpaces() ([]*models.Space, bool) {
+
+	isNewRepository := store.initializeSpacesDirectory()
+
+	spaces := []*models.Space{}
+
+	files, err := store.spaceFiles()
+	if err != nil {
+		log.Fatalf("repository: could not read spaces: %v", err)
+	}
+	for _, filename := range files {
+		namespaceType, namespace, label := parseSpaceFilename(filename)
+		space, err := store.spaceLoadFile(namespaceType, namespace, label)
+		if err != nil {
+			log.Fatalf("repository: %v (run 'cbox storage check' for details)", err)
+		}
+		spaces = append(spaces, space)
+	}
+	return spaces, isNewRepository
+}
+
+func (store *jsonStore) spaceFiles() ([]string, error) {
+	files, err := ioutil.ReadDir(store.resolve(pathSpaces))
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := []string{}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) == ".json" {
+			filenames = append(filenames, f.Name())
+		}
+	}
+	return filenames, nil
+}
+
+func parseSpaceFilename(filename string) (int, string, string) {
+	namespaceType := models.TypeNone
+	namespace := ""
+	label := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if strings.Contains(label, filenameSeparatorUser) {
+		parts := strings.Split(label, filenameSeparatorUser)
+		namespaceType = models.TypeUser
+		namespace = parts[0]
+		label = parts[1]
+	} else if strings.Contains(label, filenameSeparatorOrganization) {
+		parts := strings.Split(label, filenameSeparatorOrganization)
+		namespaceType = models.TypeOrganization
+		namespace = parts[0]
+		label = parts[1]
+	}
+	return namespaceType, namespace, label
+}
+
+func (store *jsonStore) initializeSpacesDirectory() bool {
+	spacesPath := store.resolve(pathSpaces)
+	return tools.CreateDirectoryIfNotExists(spacesPath)
+}
+
+func (store *jsonStore) LoadSpace(namespaceType int, namespace string, label string) (*models.Space, error) {
+	return store.spaceLoadFile(namespaceType, namespace, label)
+}
+
+// spaceLoadFile loads a space file, keeping track of it if written with an older schema so it can be
+// upgraded later on
+func (store *jsonStore) spaceLoadFile(namespaceType int, namespace string, label string) (*models.Space, error) {
+	spacePath := store.resolveSpaceFile(namespaceType, namespace, label)
+
+	space, version, err := parseSpaceFile(spacePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("load space '%s-%s': %w", namespace, label, ErrSpaceNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("load space '%s-%s': %v", namespace, label, err)
+	}
+
+	if version != currentSchemaVersion() {
+		store.outdated = append(store.outdated, spacePath)
+	}
+
+	return space, nil
+}
+
+func (store *jsonStore) Outdated() bool {
+	return len(store.outdated) != 0
+}
+
+// Upgrade rewrites in place (keeping a backup) the space files found written with an older schema. It
+// has to be called holding the write lock, as other processes may be loading or upgrading them too
+func (store *jsonStore) Upgrade() error {
+	outdated := store.outdated
+	store.outdated = nil
+
+	for _, spacePath := range outdated {
+		space, version, err := parseSpaceFile(spacePath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("upgrade space file '%s': %v", spacePath, err)
+		}
+		if version == currentSchemaVersion() {
+			continue // upgraded by a different process meanwhile
+		}
+
+		if err := backupSpaceFile(spacePath, version); err != nil {
+			return fmt.Errorf("upgrade space file '%s': could not backup file before upgrading it: %v", spacePath, err)
+		}
+		store.Persist(space)
+	}
+	return nil
+}
+
+// backupSpaceFile copies a space file to '<file>.v<version>.bak', or '<file>.v<version>.<n>.bak' if
+// there is a backup of that version already (so none is ever overwritten)
+func backupSpaceFile(spacePath string, version int) error {
+	data, err := ioutil.ReadFile(spacePath)
+	if err != nil {
+		return err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", spacePath, version)
+	for n := 1; ; n++ {
+		file, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			backup = fmt.Sprintf("%s.v%d.%d.bak", spacePath, version, n)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+}
+
+// parseSpaceFile reads a space file, migrating it in memory to the current schema. It returns the
+// schema version the file was written with
+func parseSpaceFile(spacePath string) (*models.Space, int, error) {
+	data, err := ioutil.ReadFile(spacePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read file '%s': %w", spacePath, err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("could not parse JSON file: %v", err)
+	}
+
+	version, err := migrateSpace(raw)
+	if err != nil {
+		return nil, version, err
+	}
+
+	var space models.Space
+	if err := remarshal(raw, &space); err != nil {
+		return nil, version, fmt.Errorf("could not parse JSON file: %v", err)
+	}
+
+	space.Selector, err = models.ParseSelector(space.ID)
+	if err != nil {
+		return nil, version, fmt.Errorf("space's ID is not a valid selector: %v", err)
+	}
+
+	for _, command := range space.Entries {
+		selector, err := models.ParseSelectorMandatoryItem(command.ID)
+		if err != nil {
+			return nil, version, fmt.Errorf("command's ID (%s) is not a valid selector: %v", command.ID, err)
+		}
+		command.Selector = selector
+	}
+
+	return &space, version, nil
+}
+
+// Check validates every space file, reporting all the problems found
+func (store *jsonStore) Check() []error {
+	problems := []error{}
+
+	files, err := store.spaceFiles()
+	if os.IsNotExist(err) {
+		return problems
+	} else if err != nil {
+		return append(problems, fmt.Errorf("could not read spaces: %v", err))
+	}
+
+	for _, filename := range files {
+		namespaceType, namespace, label := parseSpaceFilename(filename)
+
+		space, _, err := parseSpaceFile(store.resolveSpaceFile(namespaceType, namespace, label))
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %v", filename, err))
+			continue
+		}
+
+		expected := models.NewSelector(namespaceType, namespace, label, "")
+		for _, problem := range checkSpace(space, expected) {
+			problems = append(problems, fmt.Errorf("%s: %v", filename, problem))
+		}
+	}
+
+	return problems
+}
+
+func (store *jsonStore) resolveSpaceFile(namespaceType int, namespace string, label string) string {
+	filename := label
+	if namespaceType != models.TypeNone {
+		separator := filenameSeparatorUser
+		if namespaceType == models.TypeOrganization {
+			separator = filenameSeparatorOrganization
+		}
+		filename = fmt.Sprintf("%s%s%s", namespace, separator, label)
+	}
+	filename = filename + ".json"
+	return store.resolve(pathSpaces, filename)
+}
+
+// spaceFile is the content of a space file: the space itself plus the schema it was written with
+type spaceFile struct {
+	SchemaVersion int `json:"schema-version"`
+	*models.Space
+}
+
+func (store *jsonStore) Persist(space *models.Space) {
+	// not there yet when spaces are migrated from a different backend
+	store.initializeSpacesDirectory()
+
+	file := spaceFile{
+		SchemaVersion: currentSchemaVersion(),
+		Space:         space,
+	}
+
+	raw, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		log.Fatalf("repository: store space '%s': could not generate JSON: %v", space.String(), err)
+	}
+
+	spacePath := store.resolveSpaceFile(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	err = tools.WriteFileAtomic(spacePath, raw, 0644)
+	if err != nil {
+		log.Fatalf("repository: store space '%s': could not write JSON file (%s): %v", space.String(), spacePath, err)
+	}
+}
+
+func (store *jsonStore) Delete(selector *models.Selector) {
+	file := store.resolveSpaceFile(selector.NamespaceType, selector.Namespace, selector.Space)
+	err := os.Remove(file)
+	if err != nil {
+		log.Fatalf("repository: delete space '%s': %v", selector.String(), err)
+	}
+}
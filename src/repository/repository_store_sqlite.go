@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dplabs/cbox/src/models"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+const (
+	sqliteFileName = "cbox.db"
+
+	// trigram tokenizer needs at least 3 chars to match, shorter criteria fall back to LIKE
+	sqliteFTSMinCriteria = 3
+)
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS spaces (
+		id TEXT PRIMARY KEY,
+		label TEXT NOT NULL,
+		description TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS commands (
+		id TEXT PRIMARY KEY,
+		space_id TEXT NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+		label TEXT NOT NULL,
+		code TEXT NOT NULL,
+		description TEXT NOT NULL,
+		url TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS commands_space ON commands(space_id)`,
+	`CREATE TABLE IF NOT EXISTS tags (
+		command_id TEXT NOT NULL REFERENCES commands(id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (command_id, tag)
+	)`,
+	`CREATE INDEX IF NOT EXISTS tags_tag ON tags(tag)`,
+	`CREATE TABLE IF NOT EXISTS revisions (
+		command_id TEXT NOT NULL REFERENCES commands(id) ON DELETE CASCADE,
+		revision INTEGER NOT NULL,
+		code TEXT NOT NULL,
+		description TEXT NOT NULL,
+		tags TEXT NOT NULL,
+		author TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		PRIMARY KEY (command_id, revision)
+	)`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS commands_fts USING fts5(command_id UNINDEXED, label, description, code, tokenize='trigram')`,
+}
+
+// sqliteStore keeps all the spaces in a single SQLite database, indexing commands for full text search.
+// The settings are kept in config.yml, next to it
+type sqliteStore struct {
+	configFile
+	Path  string
+	db    *sql.DB
+	isNew bool
+}
+
+func newSQLiteStore(storePath string) *sqliteStore {
+	file := path.Join(storePath, sqliteFileName)
+	isNew := !sqliteDatabaseExists(storePath)
+
+	db, err := sql.Open("sqlite", file+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		log.Fatalf("repository: sqlite: could not open database '%s': %v", file, err)
+	}
+
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			log.Fatalf("repository: sqlite: could not initialize schema: %v", err)
+		}
+	}
+
+	return &sqliteStore{
+		configFile: configFile{dir: storePath},
+		Path:       file,
+		db:         db,
+		isNew:      isNew,
+	}
+}
+
+func (store *sqliteStore) LoadSpaces() ([]*models.Space, bool) {
+	rows, err := store.db.Query("SELECT id, label, description, created_at, updated_at FROM spaces")
+	if err != nil {
+		log.Fatalf("repository: sqlite: could not read spaces: %v", err)
+	}
+	defer rows.Close()
+
+	spaces := []*models.Space{}
+	for rows.Next() {
+		space, err := scanSpace(rows)
+		if err != nil {
+			log.Fatalf("repository: sqlite: could not read spaces: %v", err)
+		}
+		spaces = append(spaces, space)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatalf("repository: sqlite: could not read spaces: %v", err)
+	}
+	rows.Close()
+
+	for _, space := range spaces {
+		space.Entries, err = store.queryCommands("WHERE c.space_id = ?", space.ID)
+		if err != nil {
+			log.Fatalf("repository: sqlite: load space '%s': %v", space.ID, err)
+		}
+	}
+
+	isNewRepository := store.isNew
+	store.isNew = false
+
+	return spaces, isNewRepository
+}
+
+func (store *sqliteStore) LoadSpace(namespaceType int, namespace string, label string) (*models.Space, error) {
+	space, err := store.SpaceFind(namespaceType, namespace, label)
+	if err != nil {
+		return nil, err
+	}
+
+	space.Entries, err = store.queryCommands("WHERE c.space_id = ?", space.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load space '%s': %v", space.ID, err)
+	}
+
+	return space, nil
+}
+
+func (store *sqliteStore) Persist(space *models.Space) {
+	err := store.inTransaction(func(tx *sql.Tx) error {
+		if err := deleteSpaceRows(tx, space.ID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec("INSERT INTO spaces (id, label, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+			space.ID, space.Label, space.Description, unixTimeToInt(space.CreatedAt), unixTimeToInt(space.UpdatedAt))
+		if err != nil {
+			return err
+		}
+
+		for _, command := range space.Entries {
+			_, err := tx.Exec("INSERT INTO commands (id, space_id, label, code, description, url, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				command.ID, space.ID, command.Label, command.Code, command.Description, command.URL, unixTimeToInt(command.CreatedAt), unixTimeToInt(command.UpdatedAt))
+			if err != nil {
+				return err
+			}
+
+			for position, tag := range command.Tags {
+				if _, err := tx.Exec("INSERT OR IGNORE INTO tags (command_id, position, tag) VALUES (?, ?, ?)", command.ID, position, tag); err != nil {
+					return err
+				}
+			}
+
+			for _, revision := range command.Revisions {
+				tags, err := json.Marshal(revision.Tags)
+				if err != nil {
+					return err
+				}
+				_, err = tx.Exec("INSERT INTO revisions (command_id, revision, code, description, tags, author, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+					command.ID, revision.Number, revision.Code, revision.Description, string(tags), revision.Author, unixTimeToInt(revision.CreatedAt))
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = tx.Exec("INSERT INTO commands_fts (command_id, label, description, code) VALUES (?, ?, ?, ?)",
+				command.ID, command.Label, command.Description, command.Code)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("repository: store space '%s': sqlite: %v", space.String(), err)
+	}
+}
+
+func (store *sqliteStore) Delete(selector *models.Selector) {
+	spaceID := selector.CloneForItem("").String()
+
+	err := store.inTransaction(func(tx *sql.Tx) error {
+		return deleteSpaceRows(tx, spaceID)
+	})
+
+	if err != nil {
+		log.Fatalf("repository: delete space '%s': sqlite: %v", selector.String(), err)
+	}
+}
+
+func (store *sqliteStore) SpaceFind(namespaceType int, namespace string, label string) (*models.Space, error) {
+	spaceID := models.NewSelector(namespaceType, namespace, label, "").String()
+
+	rows, err := store.db.Query("SELECT id, label, description, created_at, updated_at FROM spaces WHERE id = ?", spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find space: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, fmt.Errorf("find space '%s': %w", spaceID, ErrSpaceNotFound)
+	}
+
+	return scanSpace(rows)
+}
+
+// Check validates the integrity of the database and the consistency of every space stored
+func (store *sqliteStore) Check() []error {
+	problems := []error{}
+
+	var integrity string
+	if err := store.db.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return append(problems, fmt.Errorf("integrity check failed: %v", err))
+	}
+	if integrity != "ok" {
+		problems = append(problems, fmt.Errorf("integrity check failed: %s", integrity))
+	}
+
+	rows, err := store.db.Query("SELECT id FROM spaces")
+	if err != nil {
+		return append(problems, fmt.Errorf("could not read spaces: %v", err))
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			problems = append(problems, fmt.Errorf("could not read spaces: %v", err))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		selector, err := models.ParseSelectorMandatorySpace(id)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("space '%s': ID is not a valid selector: %v", id, err))
+			continue
+		}
+
+		space, err := store.LoadSpace(selector.NamespaceType, selector.Namespace, selector.Space)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("space '%s': %v", id, err))
+			continue
+		}
+
+		for _, problem := range checkSpace(space, selector) {
+			problems = append(problems, fmt.Errorf("space '%s': %v", id, problem))
+		}
+	}
+
+	return problems
+}
+
+func (store *sqliteStore) SearchCommands(space *models.Selector, tag string, matcher models.Matcher) ([]*models.Command, error) {
+	conditions, args := commandFilters(space, tag)
+
+	// query terms may match with typos, so only the phrases they require can be looked up in the index
+	if query, ok := matcher.(*models.Query); ok {
+		for _, phrase := range query.RequiredPhrases() {
+			switch phrase.Field {
+			case models.SearchFieldLabel, models.SearchFieldDescription, models.SearchFieldCode:
+			default:
+				continue
+			}
+
+			if utf8.RuneCountInString(phrase.Text) >= sqliteFTSMinCriteria {
+				conditions = append(conditions, "c.id IN (SELECT command_id FROM commands_fts WHERE commands_fts MATCH ?)")
+				args = append(args, phrase.Field+` : "`+strings.Replace(phrase.Text, `"`, `""`, -1)+`"`)
+			} else {
+				conditions = append(conditions, "c."+phrase.Field+` LIKE ? ESCAPE '\'`)
+				args = append(args, "%"+escapeLike(phrase.Text)+"%")
+			}
+		}
+	}
+
+	where := ""
+	if len(conditions) != 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	commands, err := store.queryCommands(where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return models.Rank(matcher, commands), nil
+}
+
+func (store *sqliteStore) TagsList(space *models.Selector, tag string) ([]string, error) {
+	conditions, args := commandFilters(space, "")
+	if tag != "" {
+		conditions = append(conditions, "(c.label = ? OR EXISTS (SELECT 1 FROM tags ft WHERE ft.command_id = c.id AND ft.tag = ?))")
+		args = append(args, tag, tag)
+	}
+
+	query := "SELECT DISTINCT t.tag FROM tags t JOIN commands c ON c.id = t.command_id"
+	if len(conditions) != 0 {
+		query = query + " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := store.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, fmt.Errorf("list tags: %v", err)
+		}
+		tags = append(tags, t)
+	}
+
+	return tags, rows.Err()
+}
+
+func (store *sqliteStore) queryCommands(where string, args ...interface{}) ([]*models.Command, error) {
+	query := "SELECT c.id, c.label, c.code, c.description, c.url, c.created_at, c.updated_at FROM commands c " + where
+
+	rows, err := store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	commands := []*models.Command{}
+	for rows.Next() {
+		var command models.Command
+		var createdAt, updatedAt int64
+
+		err := rows.Scan(&command.ID, &command.Label, &command.Code, &command.Description, &command.URL, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		command.CreatedAt = intToUnixTime(createdAt)
+		command.UpdatedAt = intToUnixTime(updatedAt)
+
+		command.Selector, err = models.ParseSelectorMandatoryItem(command.ID)
+		if err != nil {
+			return nil, fmt.Errorf("command's ID (%s) is not a valid selector: %v", command.ID, err)
+		}
+
+		commands = append(commands, &command)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, command := range commands {
+		if command.Tags, err = store.commandTags(command.ID); err != nil {
+			return nil, err
+		}
+		if command.Revisions, err = store.commandRevisions(command.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return commands, nil
+}
+
+func (store *sqliteStore) commandTags(commandID string) ([]string, error) {
+	rows, err := store.db.Query("SELECT tag FROM tags WHERE command_id = ? ORDER BY position", commandID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (store *sqliteStore) commandRevisions(commandID string) ([]models.Revision, error) {
+	rows, err := store.db.Query("SELECT revision, code, description, tags, author, created_at FROM revisions WHERE command_id = ? ORDER BY revision", commandID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []models.Revision
+	for rows.Next() {
+		var revision models.Revision
+		var tags string
+		var createdAt int64
+		if err := rows.Scan(&revision.Number, &revision.Code, &revision.Description, &tags, &revision.Author, &createdAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(tags), &revision.Tags); err != nil {
+			return nil, fmt.Errorf("revision %d of command '%s' has invalid tags: %v", revision.Number, commandID, err)
+		}
+		revision.CreatedAt = intToUnixTime(createdAt)
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (store *sqliteStore) inTransaction(fn func(tx *sql.Tx) error) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func deleteSpaceRows(tx *sql.Tx, spaceID string) error {
+	statements := []string{
+		"DELETE FROM commands_fts WHERE command_id IN (SELECT id FROM commands WHERE space_id = ?)",
+		"DELETE FROM tags WHERE command_id IN (SELECT id FROM commands WHERE space_id = ?)",
+		"DELETE FROM revisions WHERE command_id IN (SELECT id FROM commands WHERE space_id = ?)",
+		"DELETE FROM commands WHERE space_id = ?",
+		"DELETE FROM spaces WHERE id = ?",
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, spaceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commandFilters(space *models.Selector, tag string) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if space != nil {
+		conditions = append(conditions, "c.space_id = ?")
+		args = append(args, space.CloneForItem("").String())
+	}
+	if tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM tags t WHERE t.command_id = c.id AND t.tag = ?)")
+		args = append(args, tag)
+	}
+
+	return conditions, args
+}
+
+func scanSpace(rows *sql.Rows) (*models.Space, error) {
+	var space models.Space
+	var createdAt, updatedAt int64
+
+	err := rows.Scan(&space.ID, &space.Label, &space.Description, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	space.CreatedAt = intToUnixTime(createdAt)
+	space.UpdatedAt = intToUnixTime(updatedAt)
+	space.Entries = []*models.Command{}
+
+	space.Selector, err = models.ParseSelector(space.ID)
+	if err != nil {
+		return nil, fmt.Errorf("space's ID (%s) is not a valid selector: %v", space.ID, err)
+	}
+
+	return &space, nil
+}
+
+func escapeLike(str string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(str)
+}
+
+func unixTimeToInt(t models.UnixTime) int64 {
+	return time.Time(t).Unix()
+}
+
+func intToUnixTime(ts int64) models.UnixTime {
+	return models.UnixTime(time.Unix(ts, 0))
+}
+
+func sqliteDatabaseExists(storePath string) bool {
+	_, err := os.Stat(path.Join(storePath, sqliteFileName))
+	return err == nil
+}
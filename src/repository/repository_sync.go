@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+)
+
+const pathSync = "sync.json"
+
+// SyncStateFind returns the state of the last sync of a local space with the cloud (nil if never synced)
+func (repo *Repository) SyncStateFind(selector *models.Selector) (*models.SyncState, error) {
+	repo.readLock()
+	defer repo.unlock()
+
+	states, err := repo.syncStates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, state := range states {
+		if state.Space == selector.CloneForItem("").String() {
+			return state, nil
+		}
+	}
+	return nil, nil
+}
+
+// SyncStateStore records the state of a local space after syncing it with the cloud
+func (repo *Repository) SyncStateStore(state *models.SyncState) error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	states, err := repo.syncStates()
+	if err != nil {
+		return err
+	}
+
+	updated := []*models.SyncState{state}
+	for _, s := range states {
+		if s.Space != state.Space {
+			updated = append(updated, s)
+		}
+	}
+
+	raw, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return fmt.Errorf("sync: could not generate JSON: %v", err)
+	}
+	if err := tools.WriteFileAtomic(repo.resolve(pathSync), raw, 0644); err != nil {
+		return fmt.Errorf("sync: could not write file: %v", err)
+	}
+	return nil
+}
+
+func (repo *Repository) syncStates() ([]*models.SyncState, error) {
+	data, err := ioutil.ReadFile(repo.resolve(pathSync))
+	if os.IsNotExist(err) {
+		return []*models.SyncState{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("sync: could not read file: %v", err)
+	}
+
+	states := []*models.SyncState{}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("sync: could not parse file: %v", err)
+	}
+	return states, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+)
+
+const pathTrash = "trash"
+
+// Trash keeps deleted content in its own file within the 'trash' directory, whatever the storage backend is
+func (repo *Repository) Trash(entry *models.TrashEntry) error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	tools.CreateDirectoryIfNotExists(repo.resolve(pathTrash))
+
+	raw, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("trash: could not generate JSON: %v", err)
+	}
+
+	entry.ID = fmt.Sprintf("%d-%s", time.Time(entry.DeletedAt).UnixNano(), entry.Kind())
+	for i := 1; fileExists(repo.trashFile(entry.ID)); i++ {
+		entry.ID = fmt.Sprintf("%d-%s-%d", time.Time(entry.DeletedAt).UnixNano(), entry.Kind(), i)
+	}
+
+	if err := tools.WriteFileAtomic(repo.trashFile(entry.ID), raw, 0644); err != nil {
+		return fmt.Errorf("trash: could not write file: %v", err)
+	}
+	return nil
+}
+
+// TrashList returns the content of the trash, most recently deleted first
+func (repo *Repository) TrashList() ([]*models.TrashEntry, error) {
+	repo.readLock()
+	defer repo.unlock()
+
+	files, err := ioutil.ReadDir(repo.resolve(pathTrash))
+	if os.IsNotExist(err) {
+		return []*models.TrashEntry{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("trash: could not read trash: %v", err)
+	}
+
+	entries := []*models.TrashEntry{}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		id := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+
+		data, err := ioutil.ReadFile(repo.trashFile(id))
+		if err != nil {
+			return nil, fmt.Errorf("trash: could not read file '%s': %v", f.Name(), err)
+		}
+
+		var entry models.TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("trash: could not parse file '%s': %v", f.Name(), err)
+		}
+		if err := entry.ParseSelectors(); err != nil {
+			return nil, fmt.Errorf("trash: file '%s': %v", f.Name(), err)
+		}
+		entry.ID = id
+
+		entries = append(entries, &entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+
+	return entries, nil
+}
+
+// TrashRemove deletes permanently an entry from the trash
+func (repo *Repository) TrashRemove(entry *models.TrashEntry) error {
+	repo.writeLock()
+	defer repo.unlock()
+
+	if err := os.Remove(repo.trashFile(entry.ID)); err != nil {
+		return fmt.Errorf("trash: could not remove '%s': %v", entry.Selector.String(), err)
+	}
+	return nil
+}
+
+func (repo *Repository) trashFile(id string) string {
+	return repo.resolve(pathTrash, id+".json")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
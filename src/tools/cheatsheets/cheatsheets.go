@@ -0,0 +1,59 @@
+package cheatsheets
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+const (
+	FormatNavi = "navi"
+	FormatPet  = "pet"
+	FormatTldr = "tldr"
+)
+
+var variableNameInvalidCharsRegexp = regexp.MustCompile("[^a-zA-Z0-9_-]+")
+
+// Format converts spaces from/to the files used by other cheat-sheet tools
+type Format interface {
+	// Extension of the files using the format, used to find them within directories
+	Extension() string
+	// Import reads the commands found in a file. They have no label yet, neither selectors
+	Import(r io.Reader) (*models.Space, error)
+	Export(w io.Writer, space *models.Space) error
+}
+
+func Formats() []string {
+	return []string{FormatNavi, FormatPet, FormatTldr}
+}
+
+func Get(format string) (Format, error) {
+	switch format {
+	case FormatNavi:
+		return &navi{}, nil
+	case FormatPet:
+		return &pet{}, nil
+	case FormatTldr:
+		return &tldr{}, nil
+	}
+	return nil, fmt.Errorf("unsupported format '%s' (supported: %s)", format, strings.Join(Formats(), ", "))
+}
+
+// variableName converts the name of a placeholder in other formats into a valid cbox variable name
+func variableName(name string) string {
+	return strings.Trim(variableNameInvalidCharsRegexp.ReplaceAllString(strings.TrimSpace(name), "_"), "_")
+}
+
+func newCommand(description string, code string, tags []string) *models.Command {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &models.Command{
+		Description: description,
+		Code:        code,
+		Tags:        tags,
+	}
+}
@@ -0,0 +1,141 @@
+package cheatsheets_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/cheatsheets"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type goldenCommand struct {
+	Description string   `json:"description"`
+	Code        string   `json:"code"`
+	URL         string   `json:"url"`
+	Tags        []string `json:"tags"`
+}
+
+type goldenSpace struct {
+	Label       string          `json:"label"`
+	Description string          `json:"description"`
+	Commands    []goldenCommand `json:"commands"`
+}
+
+// testRoundTrip imports a file comparing the result with its golden file, and then exports it back
+// expecting the very same file
+func testRoundTrip(t *testing.T, format string, file string) {
+	f, err := cheatsheets.Get(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, err := ioutil.ReadFile(path.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	space, err := f.Import(bytes.NewReader(original))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	imported := goldenSpace{Label: space.Label, Description: space.Description, Commands: []goldenCommand{}}
+	for _, command := range space.Entries {
+		imported.Commands = append(imported.Commands, goldenCommand{command.Description, command.Code, command.URL, command.Tags})
+	}
+	actual, err := json.MarshalIndent(imported, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := path.Join("testdata", file+".golden.json")
+	if *update {
+		if err := ioutil.WriteFile(golden, append(actual, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run the tests with -update to create it)", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(expected), bytes.TrimSpace(actual)) {
+		t.Errorf("imported content does not match %s:\n%s", golden, actual)
+	}
+
+	var exported bytes.Buffer
+	if err := f.Export(&exported, space); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !bytes.Equal(exported.Bytes(), original) {
+		t.Errorf("exporting the imported content does not give back %s:\n%s", file, exported.String())
+	}
+}
+
+func TestNaviRoundTrip(t *testing.T) {
+	testRoundTrip(t, cheatsheets.FormatNavi, "git.cheat")
+}
+
+func TestPetRoundTrip(t *testing.T) {
+	testRoundTrip(t, cheatsheets.FormatPet, "snippet.toml")
+}
+
+func TestTldrRoundTrip(t *testing.T) {
+	testRoundTrip(t, cheatsheets.FormatTldr, "tar.md")
+}
+
+func TestTldrPlaceholdersBecomeVariables(t *testing.T) {
+	f, _ := cheatsheets.Get(cheatsheets.FormatTldr)
+
+	space, err := f.Import(strings.NewReader("# cp\n\n- Copy a file:\n\n`cp {{path/to/source file}} {{path/to/target}}`\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "cp {{path_to_source_file}} {{path_to_target}}"
+	if len(space.Entries) != 1 || space.Entries[0].Code != expected {
+		t.Errorf("expected a command with code '%s', got %+v", expected, space.Entries)
+	}
+}
+
+func TestTldrRoundTripWithoutDescription(t *testing.T) {
+	f, _ := cheatsheets.Get(cheatsheets.FormatTldr)
+
+	original := &models.Space{Label: "ls", Entries: []*models.Command{{Label: "list-files", Code: "ls -la"}}}
+
+	var exported bytes.Buffer
+	if err := f.Export(&exported, original); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	space, err := f.Import(&exported)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(space.Entries) != 1 || space.Entries[0].Description != "list-files" || space.Entries[0].Code != "ls -la" {
+		t.Errorf("commands without description should be described by their label, got %+v", space.Entries)
+	}
+}
+
+func TestTldrExportMultilineCode(t *testing.T) {
+	f, _ := cheatsheets.Get(cheatsheets.FormatTldr)
+
+	space := &models.Space{Label: "loop", Entries: []*models.Command{{Label: "loop", Description: "Loop", Code: "for i in 1 2 3; do\n  echo $i\ndone"}}}
+
+	var exported bytes.Buffer
+	if err := f.Export(&exported, space); err == nil {
+		t.Errorf("commands spanning several lines should not be exported, got:\n%s", exported.String())
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	if _, err := cheatsheets.Get("unknown"); err == nil {
+		t.Errorf("unknown formats should not be supported")
+	}
+}
@@ -0,0 +1,183 @@
+package cheatsheets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+)
+
+var (
+	naviVariableRegexp = regexp.MustCompile(`<([a-zA-Z_][a-zA-Z0-9_-]*)>`)
+	// navi variables are filled with the output of a command, defaults are exported as a plain echo
+	naviDefaultRegexp = regexp.MustCompile(`^\$\s*([a-zA-Z_][a-zA-Z0-9_-]*)\s*:\s*echo\s+(.*)$`)
+)
+
+// navi reads and writes navi .cheat files:
+//
+//	% tag1, tag2
+//
+//	# description
+//	code with <variable>
+//	$ variable: echo 'default'
+//
+// Tags apply to every command below them. Variables suggestions other than a plain echo are ignored
+type navi struct{}
+
+type naviCommand struct {
+	command  *models.Command
+	defaults map[string]string
+}
+
+func (format *navi) Extension() string {
+	return ".cheat"
+}
+
+func (format *navi) Import(r io.Reader) (*models.Space, error) {
+	space := &models.Space{Entries: []*models.Command{}}
+
+	tags := []string{}
+	description := ""
+	section := []*naviCommand{}
+	var current *naviCommand
+
+	endSection := func() {
+		for _, c := range section {
+			c.command.Code = naviToCode(c.command.Code, c.defaults, section)
+			space.Entries = append(space.Entries, c.command)
+		}
+		section = []*naviCommand{}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+
+		switch {
+		case line == "":
+			current = nil
+		case strings.HasPrefix(line, "%"):
+			endSection()
+			tags = parseNaviTags(strings.TrimPrefix(line, "%"))
+			description = ""
+		case strings.HasPrefix(line, "#"):
+			current = nil
+			description = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		case strings.HasPrefix(line, "$"):
+			current = nil
+			if m := naviDefaultRegexp.FindStringSubmatch(line); m != nil {
+				setNaviDefault(section, m[1], unquote(strings.TrimSpace(m[2])))
+			}
+		case strings.HasPrefix(line, ";"), strings.HasPrefix(line, "@"):
+			// comments and extended cheats are not supported
+		default:
+			if current != nil {
+				current.command.Code += "\n" + line
+				continue
+			}
+			current = &naviCommand{
+				command:  newCommand(description, line, append([]string{}, tags...)),
+				defaults: make(map[string]string),
+			}
+			section = append(section, current)
+			description = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("navi: %v", err)
+	}
+	endSection()
+
+	return space, nil
+}
+
+func parseNaviTags(line string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(line, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tools.GenerateLabel(tag, func(string) bool { return false }))
+		}
+	}
+	return tags
+}
+
+// setNaviDefault assigns the default to the commands of the section using the variable that have none yet
+func setNaviDefault(section []*naviCommand, name string, value string) {
+	for _, c := range section {
+		if _, ok := c.defaults[name]; !ok && strings.Contains(c.command.Code, "<"+name+">") {
+			c.defaults[name] = value
+		}
+	}
+}
+
+// naviToCode replaces navi variables with cbox ones, writing the default (if any) on its first occurrence.
+// Variables with no default assigned take the first one defined for them in the section
+func naviToCode(code string, defaults map[string]string, section []*naviCommand) string {
+	done := make(map[string]bool)
+	return naviVariableRegexp.ReplaceAllStringFunc(code, func(placeholder string) string {
+		name := naviVariableRegexp.FindStringSubmatch(placeholder)[1]
+		variable := models.Variable{Name: name}
+		if !done[name] {
+			variable.Default, variable.HasDefault = defaults[name]
+			for _, c := range section {
+				if variable.HasDefault {
+					break
+				}
+				variable.Default, variable.HasDefault = c.defaults[name]
+			}
+		}
+		done[name] = true
+		return variable.Placeholder()
+	})
+}
+
+func (format *navi) Export(w io.Writer, space *models.Space) error {
+	out := bufio.NewWriter(w)
+
+	previousTags := ""
+	for i, command := range space.Entries {
+		tags := strings.Join(command.Tags, ", ")
+		if i == 0 || tags != previousTags {
+			fmt.Fprintf(out, "%s\n\n", strings.TrimSpace("% "+tags))
+			previousTags = tags
+		}
+
+		defaults := []models.Variable{}
+		code := command.ReplaceVariables(func(variable models.Variable) string {
+			if variable.HasDefault {
+				defaults = append(defaults, variable)
+			}
+			return "<" + variable.Name + ">"
+		})
+
+		if command.Description != "" {
+			fmt.Fprintf(out, "# %s\n", command.Description)
+		}
+		fmt.Fprintf(out, "%s\n", code)
+		for _, variable := range defaults {
+			fmt.Fprintf(out, "$ %s: echo %s\n", variable.Name, quote(variable.Default))
+		}
+		fmt.Fprintf(out, "\n")
+	}
+
+	return out.Flush()
+}
+
+func quote(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", `'\''`) + "'"
+}
+
+func unquote(str string) string {
+	if len(str) >= 2 && str[0] == '\'' && str[len(str)-1] == '\'' {
+		return strings.ReplaceAll(str[1:len(str)-1], `'\''`, "'")
+	}
+	if len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
+		return str[1 : len(str)-1]
+	}
+	return str
+}
@@ -0,0 +1,99 @@
+package cheatsheets
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dplabs/cbox/src/models"
+)
+
+// pet variables look like <name> or <name=default value>
+var petVariableRegexp = regexp.MustCompile(`<([^<>=\s][^<>=]*?)(=([^<>]*))?>`)
+
+// pet reads and writes pet snippet files (TOML):
+//
+//	[[snippets]]
+//	  description = "description"
+//	  command = "code with <variable=default>"
+//	  tag = ["tag1", "tag2"]
+//	  output = ""
+type pet struct{}
+
+type petSnippets struct {
+	Snippets []petSnippet `toml:"snippets"`
+}
+
+type petSnippet struct {
+	Description string   `toml:"description"`
+	Command     string   `toml:"command"`
+	Tag         []string `toml:"tag"`
+	Output      string   `toml:"output"`
+}
+
+func (format *pet) Extension() string {
+	return ".toml"
+}
+
+func (format *pet) Import(r io.Reader) (*models.Space, error) {
+	var snippets petSnippets
+	if _, err := toml.DecodeReader(r, &snippets); err != nil {
+		return nil, fmt.Errorf("pet: %v", err)
+	}
+
+	space := &models.Space{Entries: []*models.Command{}}
+	for _, snippet := range snippets.Snippets {
+		tags := []string{}
+		for _, tag := range snippet.Tag {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, strings.ToLower(tag))
+			}
+		}
+		space.Entries = append(space.Entries, newCommand(snippet.Description, petToCode(snippet.Command), tags))
+	}
+
+	return space, nil
+}
+
+func petToCode(command string) string {
+	return petVariableRegexp.ReplaceAllStringFunc(command, func(placeholder string) string {
+		match := petVariableRegexp.FindStringSubmatch(placeholder)
+		variable := models.Variable{
+			Name:       variableName(match[1]),
+			Default:    match[3],
+			HasDefault: match[2] != "",
+		}
+		return variable.Placeholder()
+	})
+}
+
+func (format *pet) Export(w io.Writer, space *models.Space) error {
+	snippets := petSnippets{Snippets: []petSnippet{}}
+
+	for _, command := range space.Entries {
+		code := command.ReplaceVariables(func(variable models.Variable) string {
+			if variable.HasDefault {
+				return fmt.Sprintf("<%s=%s>", variable.Name, variable.Default)
+			}
+			return fmt.Sprintf("<%s>", variable.Name)
+		})
+
+		tags := command.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		snippets.Snippets = append(snippets.Snippets, petSnippet{
+			Description: command.Description,
+			Command:     code,
+			Tag:         tags,
+		})
+	}
+
+	if err := toml.NewEncoder(w).Encode(snippets); err != nil {
+		return fmt.Errorf("pet: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package cheatsheets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+)
+
+var (
+	tldrVariableRegexp = regexp.MustCompile(`{{(.*?)}}`)
+	tldrURLRegexp      = regexp.MustCompile(`^More information: <(.*)>\.?$`)
+)
+
+// tldr reads and writes tldr pages (markdown):
+//
+//	# title
+//
+//	> Description of the page.
+//	> More information: <url>.
+//
+//	- Description of the command:
+//
+//	`code with {{placeholder}}`
+//
+// The title and description of the page belong to the space, its URL to every command. Placeholders
+// are free text in tldr, so they are converted into valid variable names and have no defaults. Commands
+// spanning several lines can't be exported, and the ones without description are described by their label
+type tldr struct{}
+
+func (format *tldr) Extension() string {
+	return ".md"
+}
+
+func (format *tldr) Import(r io.Reader) (*models.Space, error) {
+	space := &models.Space{Entries: []*models.Command{}}
+
+	url := ""
+	descriptions := []string{}
+	description := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "# "):
+			space.Label = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		case strings.HasPrefix(line, ">"):
+			text := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			if m := tldrURLRegexp.FindStringSubmatch(text); m != nil {
+				url = m[1]
+			} else {
+				descriptions = append(descriptions, text)
+			}
+		case strings.HasPrefix(line, "- "):
+			description = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "- ")), ":")
+		case strings.HasPrefix(line, "`") && strings.HasSuffix(line, "`") && len(line) > 1:
+			command := newCommand(description, tldrToCode(line[1:len(line)-1]), nil)
+			space.Entries = append(space.Entries, command)
+			description = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("tldr: %v", err)
+	}
+
+	for _, command := range space.Entries {
+		command.URL = url
+	}
+	space.Description = strings.Join(descriptions, " ")
+
+	return space, nil
+}
+
+func tldrToCode(code string) string {
+	return tldrVariableRegexp.ReplaceAllStringFunc(code, func(placeholder string) string {
+		variable := models.Variable{Name: variableName(tldrVariableRegexp.FindStringSubmatch(placeholder)[1])}
+		return variable.Placeholder()
+	})
+}
+
+func (format *tldr) Export(w io.Writer, space *models.Space) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# %s\n\n", space.Label)
+	if space.Description != "" {
+		fmt.Fprintf(out, "> %s\n", space.Description)
+	}
+	if url := commonURL(space.Entries); url != "" {
+		fmt.Fprintf(out, "> More information: <%s>.\n", url)
+	}
+
+	for _, command := range space.Entries {
+		code := command.ReplaceVariables(func(variable models.Variable) string {
+			return "{{" + variable.Name + "}}"
+		})
+		if strings.Contains(code, "\n") {
+			return fmt.Errorf("command '%s' spans several lines, which tldr pages don't support", command.Label)
+		}
+
+		description := command.Description
+		if description == "" {
+			description = command.Label
+		}
+		fmt.Fprintf(out, "\n- %s:\n\n`%s`\n", description, code)
+	}
+
+	return out.Flush()
+}
+
+// commonURL returns the URL shared by all the commands, if any
+func commonURL(commands []*models.Command) string {
+	if len(commands) == 0 {
+		return ""
+	}
+	url := commands[0].URL
+	for _, command := range commands {
+		if command.URL != url {
+			return ""
+		}
+	}
+	return url
+}
@@ -126,21 +126,21 @@ func PrintError(msg string) {
 }
 
 func PrintSuccess(msg string) {
-	tty.Print("%s\n", tty.ColorGreen(msg))
+	printMessage("%s\n", tty.ColorGreen(msg))
 }
 
 func PrintInfo(msg string) {
-	tty.Print("%s\n", tty.ColorCyan(msg))
+	printMessage("%s\n", tty.ColorCyan(msg))
 }
 
 func PrintWarning(msg string) {
-	tty.Print("%s %s\n", tty.ColorBgRed(" WARNING "), tty.ColorMagenta(msg))
+	printMessage("%s %s\n", tty.ColorBgRed(" WARNING "), tty.ColorMagenta(msg))
 }
 
 func PrintAction(msg string) {
-	tty.Print("** %s **\n\n", tty.ColorBoldYellow(msg))
+	printMessage("** %s **\n\n", tty.ColorBoldYellow(msg))
 }
 
 func PrintDevWarning() {
-	tty.Print("\n%s\n\n", tty.ColorBgRed("  !!! You are using cbox's TEST cloud !!!   "))
+	printMessage("\n%s\n\n", tty.ColorBgRed("  !!! You are using cbox's TEST cloud !!!   "))
 }
@@ -0,0 +1,87 @@
+package console
+
+import (
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dplabs/cbox/src/tools/tui"
+)
+
+// SelectEntries lets the user pick any number of entries through fzf or the built-in picker, depending
+// on the listing mode (use TAB to select several of them), returning the ones picked in the same order
+// they were given. Entries may span several lines
+func SelectEntries(header string, entries []string, listingMode string) []string {
+	if listingMode == "tui" {
+		return selectEntriesTUI(header, entries)
+	}
+
+	args := []string{"--multi", "--no-sort", "--read0", "--print0", "--ansi", "--exact"}
+	if header != "" {
+		args = append(args, "--header="+header)
+	}
+
+	fzfProcess := exec.Command("fzf", args...)
+	stdin, err := fzfProcess.StdinPipe()
+	if err != nil {
+		log.Fatalf("console: interactive mode: failed to spawn process and get its stdin: %v", err)
+	}
+
+	fzfProcess.Stderr = os.Stderr
+
+	go func() {
+		for _, entry := range entries {
+			io.WriteString(stdin, entry)
+			io.WriteString(stdin, "\x00")
+		}
+		stdin.Close()
+	}()
+
+	out, err := fzfProcess.Output()
+	if err != nil {
+		exitCode := fzfProcess.ProcessState.ExitCode()
+		if exitCode == -1 {
+			log.Fatalf("console: interactive mode: 'fzf' failed to start: %v", err)
+		} else if exitCode == 2 {
+			log.Fatalf("console: interactive mode: 'fzf' returned an internal error: %v", err)
+		}
+		return []string{}
+	}
+
+	picked := make(map[string]bool)
+	for _, entry := range strings.Split(string(out), "\x00") {
+		picked[entry] = true
+	}
+
+	selected := []string{}
+	for _, entry := range entries {
+		if picked[entry] {
+			selected = append(selected, entry)
+		}
+	}
+	return selected
+}
+
+func selectEntriesTUI(header string, entries []string) []string {
+	items := []tui.Item{}
+	for _, entry := range entries {
+		entry := entry
+		items = append(items, tui.Item{
+			Line:    entry,
+			Preview: func() string { return entry },
+		})
+	}
+
+	picked, _, err := tui.Pick(items, tui.Options{Header: header, Multi: true})
+	if err != nil {
+		log.Fatalf("console: interactive mode: %v", err)
+	}
+
+	selected := []string{}
+	for _, index := range picked {
+		selected = append(selected, entries[index])
+	}
+	return selected
+}
@@ -0,0 +1,176 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	frontMatterDelimiter = "---"
+	editorErrorPrefix    = "# ERROR: "
+)
+
+type commandDocument struct {
+	Label       string   `yaml:"label"`
+	Description string   `yaml:"description"`
+	URL         string   `yaml:"url"`
+	Tags        []string `yaml:"tags"`
+}
+
+type spaceDocument struct {
+	Label       string `yaml:"label"`
+	Description string `yaml:"description"`
+}
+
+// EditCommandInEditor opens the command in $EDITOR as a YAML front matter (label, description, URL and
+// tags) followed by its code. The editor is opened again until the document is valid and passes check
+func EditCommandInEditor(command *models.Command, check func(command *models.Command) error) error {
+	header, err := yaml.Marshal(commandDocument{
+		Label:       command.Label,
+		Description: command.Description,
+		URL:         command.URL,
+		Tags:        command.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("could not generate document: %v", err)
+	}
+	document := fmt.Sprintf("%s\n%s%s\n%s\n", frontMatterDelimiter, header, frontMatterDelimiter, command.Code)
+
+	return editDocument(command.Label+"-*.md", document, func(text string) error {
+		front, code, err := splitFrontMatter(text)
+		if err != nil {
+			return err
+		}
+
+		var parsed commandDocument
+		if err := yaml.UnmarshalStrict([]byte(front), &parsed); err != nil {
+			return fmt.Errorf("invalid front matter: %v", err)
+		}
+
+		edited := *command
+		edited.Label = strings.ToLower(strings.TrimSpace(parsed.Label))
+		edited.Description = strings.TrimSpace(parsed.Description)
+		edited.URL = strings.TrimSpace(parsed.URL)
+		edited.Code = strings.TrimRight(code, "\r\n")
+		edited.Tags = []string{}
+		for _, tag := range parsed.Tags {
+			if err := ValidateTag(tag); err != nil {
+				return err
+			}
+			edited.TagAdd(tag)
+		}
+
+		if err := ValidateCommand(&edited); err != nil {
+			return err
+		}
+		if err := check(&edited); err != nil {
+			return err
+		}
+
+		*command = edited
+		return nil
+	})
+}
+
+// EditSpaceInEditor opens the space in $EDITOR as a YAML document (label and description). The editor
+// is opened again until the document is valid and passes check
+func EditSpaceInEditor(space *models.Space, check func(space *models.Space) error) error {
+	document, err := yaml.Marshal(spaceDocument{Label: space.Label, Description: space.Description})
+	if err != nil {
+		return fmt.Errorf("could not generate document: %v", err)
+	}
+
+	return editDocument(space.Label+"-*.yaml", string(document), func(text string) error {
+		var parsed spaceDocument
+		if err := yaml.UnmarshalStrict([]byte(text), &parsed); err != nil {
+			return fmt.Errorf("invalid document: %v", err)
+		}
+
+		label := strings.ToLower(strings.TrimSpace(parsed.Label))
+		if label == "" {
+			return fmt.Errorf("a label is required")
+		}
+		if !CheckValidChars(label) {
+			return fmt.Errorf("invalid characters in label '%s' (only lowercase letters, digits and '-' allowed)", label)
+		}
+
+		edited := *space
+		edited.Label = label
+		edited.Description = strings.TrimSpace(parsed.Description)
+		if err := check(&edited); err != nil {
+			return err
+		}
+
+		space.Label = edited.Label
+		space.Description = edited.Description
+		return nil
+	})
+}
+
+// editDocument writes a document into a temporary file and opens it in the editor until parse accepts
+// its content, adding the errors found as comments at its top. Emptying the document cancels the edition
+func editDocument(pattern string, document string, parse func(text string) error) error {
+	file, err := os.CreateTemp("", "cbox-"+pattern)
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %v", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	editor := tools.ResolveEditor()
+	for {
+		if err := os.WriteFile(file.Name(), []byte(document), 0600); err != nil {
+			return fmt.Errorf("could not write temporary file: %v", err)
+		}
+		if err := tools.EditFile(editor, file.Name()); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(file.Name())
+		if err != nil {
+			return fmt.Errorf("could not read temporary file: %v", err)
+		}
+
+		text := withoutEditorErrors(string(data))
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("edition cancelled")
+		}
+
+		err = parse(text)
+		if err == nil {
+			return nil
+		}
+		document = fmt.Sprintf("%s%v (empty the file to cancel)\n%s", editorErrorPrefix, strings.ReplaceAll(err.Error(), "\n", " "), text)
+	}
+}
+
+func withoutEditorErrors(text string) string {
+	lines := []string{}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if !strings.HasPrefix(line, editorErrorPrefix) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// splitFrontMatter separates the YAML front matter of a document, enclosed between '---' lines, from
+// the rest of it
+func splitFrontMatter(text string) (string, string, error) {
+	lines := strings.SplitAfter(strings.TrimLeft(text, "\r\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return "", "", fmt.Errorf("the document must start with a '%s' line", frontMatterDelimiter)
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			return strings.Join(lines[1:i], ""), strings.Join(lines[i+1:], ""), nil
+		}
+	}
+	return "", "", fmt.Errorf("missing '%s' line closing the front matter", frontMatterDelimiter)
+}
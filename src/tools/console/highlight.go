@@ -0,0 +1,64 @@
+package console
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+var (
+	highlightColor = tty.ColorBgYellow
+
+	highlighter models.Matcher
+)
+
+// HighlightMatches makes commands displayed from now on highlight the parts found by a search
+func HighlightMatches(matcher models.Matcher) {
+	highlighter = matcher
+}
+
+func plain(text string) string {
+	return text
+}
+
+// highlight colors the text of a field, using the highlight color for the parts matched by the search
+func highlight(field string, text string, color func(string) string) string {
+	if highlighter == nil {
+		return color(text)
+	}
+
+	spans := highlighter.Spans(field, text)
+	if len(spans) == 0 {
+		return color(text)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var out strings.Builder
+	position := 0
+	for _, span := range spans {
+		if span[1] <= position {
+			continue
+		}
+		if span[0] > position {
+			out.WriteString(color(text[position:span[0]]))
+		} else {
+			span[0] = position
+		}
+		out.WriteString(highlightColor(text[span[0]:span[1]]))
+		position = span[1]
+	}
+	if position < len(text) {
+		out.WriteString(color(text[position:]))
+	}
+	return out.String()
+}
+
+func highlightTags(tags []string) string {
+	highlighted := []string{}
+	for _, tag := range tags {
+		highlighted = append(highlighted, highlight(models.SearchFieldTag, tag, tagsColor))
+	}
+	return strings.Join(highlighted, tagsColor(", "))
+}
@@ -0,0 +1,66 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+var diffStatusColors = map[string]func(string) string{
+	models.DiffAdded:   diffAddedColor,
+	models.DiffChanged: tty.ColorYellow,
+	models.DiffRemoved: diffRemovedColor,
+}
+
+// PrintSpaceDiff displays the differences between a local space and its cloud counterpart, as unified
+// diffs of every command that differs, and how many commands would be added, changed or removed remotely
+// TSV columns: label, status, fields changed
+func PrintSpaceDiff(header string, diff *models.SpaceDiff) {
+	if machineReadable() {
+		rows := [][]string{}
+		for _, command := range diff.Commands {
+			rows = append(rows, []string{command.Label, command.Status, strings.Join(command.Fields, ",")})
+		}
+		printStructured(diff, rows)
+		return
+	}
+
+	printHeader(header)
+
+	if !diff.Published {
+		tty.Print("\n  %s\n", tty.ColorYellow(fmt.Sprintf("'%s' not published yet", diff.Remote)))
+	}
+
+	for _, command := range diff.Commands {
+		if command.Status == models.DiffUnchanged {
+			continue
+		}
+
+		tty.Print("\n%s %s\n\n", diffStatusColors[command.Status](fmt.Sprintf("%-8s", command.Status)), labelColor(command.Label))
+		for i, line := range strings.Split(strings.TrimSuffix(command.Diff, "\n"), "\n") {
+			switch {
+			case i < 2:
+				tty.Print("  %s\n", tty.ColorBoldWhite(line))
+			case strings.HasPrefix(line, "@@"):
+				tty.Print("  %s\n", tty.ColorCyan(line))
+			case strings.HasPrefix(line, "+"):
+				tty.Print("  %s\n", diffAddedColor(line))
+			case strings.HasPrefix(line, "-"):
+				tty.Print("  %s\n", diffRemovedColor(line))
+			default:
+				tty.Print("  %s\n", line)
+			}
+		}
+	}
+
+	summary := diff.Summary
+	if summary.Added+summary.Changed+summary.Removed == 0 {
+		tty.Print("\n  No differences: the cloud space is up to date\n\n")
+	} else {
+		tty.Print("\n  %d added, %d changed, %d removed, %d unchanged\n\n", summary.Added, summary.Changed, summary.Removed, summary.Unchanged)
+	}
+
+	printFooter(header)
+}
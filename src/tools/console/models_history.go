@@ -0,0 +1,95 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+var (
+	revisionColor    = tty.ColorBoldBlue
+	diffAddedColor   = tty.ColorGreen
+	diffRemovedColor = tty.ColorRed
+)
+
+// PrintCommandHistory displays every revision of a command, showing what changed from the previous one
+// TSV columns: revision, author, created at, description, tags, code
+func PrintCommandHistory(header string, cmd *models.Command) {
+	if machineReadable() {
+		revisions := cmd.Revisions
+		if revisions == nil {
+			revisions = []models.Revision{}
+		}
+		rows := [][]string{}
+		for _, revision := range revisions {
+			rows = append(rows, []string{fmt.Sprint(revision.Number), revision.Author, tsvTime(revision.CreatedAt), revision.Description, strings.Join(revision.Tags, ","), revision.Code})
+		}
+		printStructured(revisions, rows)
+		return
+	}
+
+	printHeader(header)
+
+	if len(cmd.Revisions) == 0 {
+		tty.Print("\n  No revisions recorded yet: command never edited\n\n")
+		printFooter(header)
+		return
+	}
+
+	var previous *models.Revision
+	for i := range cmd.Revisions {
+		revision := &cmd.Revisions[i]
+
+		author := ""
+		if revision.Author != "" {
+			author = fmt.Sprintf(" by %s", revision.Author)
+		}
+		current := ""
+		if i == len(cmd.Revisions)-1 {
+			current = " (current)"
+		}
+		tty.Print("\n%s%s %s\n\n", revisionColor(fmt.Sprintf("Revision %d", revision.Number)), current, dateColor(fmt.Sprintf("%s%s", revision.CreatedAt.String(), author)))
+
+		if previous == nil {
+			previous = &models.Revision{}
+		}
+		printFieldDiff("Description", previous.Description, revision.Description)
+		printFieldDiff("Tags", strings.Join(previous.Tags, ", "), strings.Join(revision.Tags, ", "))
+		printCodeDiff(previous.Code, revision.Code)
+
+		previous = revision
+	}
+	tty.Print("\n")
+
+	printFooter(header)
+}
+
+func printFieldDiff(field string, previous string, current string) {
+	if previous == current {
+		return
+	}
+	if previous != "" {
+		tty.Print("  %s %s: %s\n", diffRemovedColor("-"), field, diffRemovedColor(previous))
+	}
+	tty.Print("  %s %s: %s\n", diffAddedColor("+"), field, diffAddedColor(current))
+}
+
+func printCodeDiff(previous string, current string) {
+	if previous == current {
+		return
+	}
+	tty.Print("\n")
+	for _, line := range tools.DiffLines(previous, current) {
+		switch line.Op {
+		case tools.DiffAdded:
+			tty.Print("  %s\n", diffAddedColor("+ "+line.Text))
+		case tools.DiffRemoved:
+			tty.Print("  %s\n", diffRemovedColor("- "+line.Text))
+		default:
+			tty.Print("    %s\n", line.Text)
+		}
+	}
+}
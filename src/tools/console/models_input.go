@@ -52,6 +52,29 @@ func ReadCommand(space *models.Space) *models.Command {
 	return &command
 }
 
+// ReadVariables asks for the value of every variable not already present in values
+func ReadVariables(variables []models.Variable, values map[string]string) map[string]string {
+	for _, variable := range variables {
+		if _, ok := values[variable.Name]; ok {
+			continue
+		}
+
+		if !variable.HasDefault {
+			values[variable.Name] = ReadString(variable.Name, NOT_EMPTY_VALUES)
+		} else if tty.SkipQuestions {
+			values[variable.Name] = variable.Default
+		} else {
+			value := ReadString(fmt.Sprintf("%s (default: %s)", variable.Name, variable.Default))
+			if strings.TrimSpace(value) == "" {
+				value = variable.Default
+			}
+			values[variable.Name] = value
+		}
+	}
+
+	return values
+}
+
 func EditCommand(command *models.Command) {
 	command.Label = strings.ToLower(EditString("Label", command.Label, ONLY_VALID_CHARS, NOT_EMPTY_VALUES))
 	command.Description = EditString("Description", command.Description)
@@ -61,6 +84,33 @@ func EditCommand(command *models.Command) {
 	tty.Print("\n")
 }
 
+// ValidateCommand checks the values of a command not asked for interactively
+func ValidateCommand(command *models.Command) error {
+	if command.Label == "" {
+		return fmt.Errorf("a label is required")
+	}
+	if !CheckValidChars(command.Label) {
+		return fmt.Errorf("invalid characters in label '%s' (only lowercase letters, digits and '-' allowed)", command.Label)
+	}
+	for _, tag := range command.Tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(command.Code) == "" {
+		return fmt.Errorf("the code can't be empty")
+	}
+	return nil
+}
+
+// ValidateTag checks a tag given before adding it to a command (as adding it lowercases it)
+func ValidateTag(tag string) error {
+	if !CheckValidChars(tag) {
+		return fmt.Errorf("invalid characters in tag '%s' (only lowercase letters, digits and '-' allowed)", tag)
+	}
+	return nil
+}
+
 func ReadSpace() *models.Space {
 	space := models.Space{
 		Label:       strings.ToLower(ReadString("Label", NOT_EMPTY_VALUES, ONLY_VALID_CHARS)),
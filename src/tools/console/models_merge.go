@@ -0,0 +1,78 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+const (
+	ResolutionKeepMine   = "keep mine"
+	ResolutionKeepTheirs = "keep theirs"
+	ResolutionEdit       = "edit merged in $EDITOR"
+)
+
+var resolutions = map[string]string{
+	ResolutionKeepMine:   models.MergeMine,
+	ResolutionKeepTheirs: models.MergeTheirs,
+	ResolutionEdit:       models.MergeEdited,
+}
+
+// ResolveConflicts shows the fields of a command changed differently on both sides and asks how to
+// resolve them: keeping the local values, the remote ones or editing the merged command in $EDITOR
+// (where both values are found between conflict markers). Returns the resolution picked
+// (defaultResolution if questions are skipped) and the command resolved
+func ResolveConflicts(merge *models.CommandMerge, defaultResolution string) (string, *models.Command, error) {
+	header := fmt.Sprintf("Conflicts in '%s'", merge.Label)
+	printHeader(header)
+	for _, conflict := range merge.Conflicts {
+		tty.Print("\n  %s\n", tty.ColorBoldWhite(strings.ToUpper(conflict.Field[:1])+conflict.Field[1:]))
+		printConflictValue(diffRemovedColor("< mine  "), conflict.Mine)
+		printConflictValue(diffAddedColor("> theirs"), conflict.Theirs)
+	}
+	tty.Print("\n")
+	printFooter(header)
+
+	defaultOption := ResolutionKeepMine
+	if defaultResolution == models.MergeTheirs {
+		defaultOption = ResolutionKeepTheirs
+	}
+
+	option := tty.Choose(fmt.Sprintf("How to resolve the conflicts in '%s'?", merge.Label), []string{ResolutionKeepMine, ResolutionKeepTheirs, ResolutionEdit}, defaultOption)
+	resolution, found := resolutions[option]
+	if !found {
+		return "", nil, fmt.Errorf("conflicts in '%s' not resolved", merge.Label)
+	}
+	if resolution != models.MergeEdited {
+		return resolution, merge.Resolve(resolution), nil
+	}
+
+	edited := merge.WithConflictMarkers()
+	err := EditCommandInEditor(edited, func(command *models.Command) error {
+		if command.Label != merge.Label {
+			return fmt.Errorf("the label can't be changed while merging")
+		}
+		if models.HasConflictMarkers(command) {
+			return fmt.Errorf("some conflict markers are still present")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	edited.UpdatedAt = models.UnixTimeNow()
+	return resolution, edited, nil
+}
+
+func printConflictValue(side string, value string) {
+	if value == "" {
+		value = "(empty)"
+	}
+	lines := strings.Split(value, "\n")
+	tty.Print("    %s  %s\n", side, lines[0])
+	for _, line := range lines[1:] {
+		tty.Print("              %s\n", line)
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"os/exec"
 	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/dplabs/cbox/src/models"
 	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/src/tools/tui"
 )
 
 var (
@@ -26,10 +28,33 @@ var (
 	dateColor                  = tty.ColorBoldBlack
 	urlColor                   = tty.ColorGreen
 	separatorColor             = tty.ColorYellow
+	variableColor              = tty.ColorMagenta
+	remoteColor                = tty.ColorBoldCyan
+
+	// commands being listed which come from the cloud instead of from local spaces
+	remoteCommands = map[*models.Command]bool{}
 )
 
 const (
 	timestampFormat = "(Updated: %s - Created: %s)"
+
+	ActionNone   = ""
+	ActionView   = "view"
+	ActionRun    = "run"
+	ActionCopy   = "copy"
+	ActionEdit   = "edit"
+	ActionDelete = "delete"
+
+	// SortRelevance keeps commands in the order given, as search results come most relevant first
+	SortRelevance = "relevance"
+
+	fzfKeyRun  = "ctrl-r"
+	fzfKeyCopy = "ctrl-y"
+
+	tuiKeyRun    = "ctrl-r"
+	tuiKeyCopy   = "ctrl-y"
+	tuiKeyEdit   = "ctrl-e"
+	tuiKeyDelete = "ctrl-d"
 )
 
 func selector(selector *models.Selector) string {
@@ -38,7 +63,7 @@ func selector(selector *models.Selector) string {
 
 	if selector.Item != "" {
 		format = "%s"
-		parts = append(parts, labelColor(selector.Item))
+		parts = append(parts, highlight(models.SearchFieldLabel, selector.Item, labelColor))
 	}
 
 	if selector.Space != "" {
@@ -61,11 +86,15 @@ func selector(selector *models.Selector) string {
 
 func commandSummary(cmd *models.Command) string {
 	timestamp := fmt.Sprintf(timestampFormat, cmd.UpdatedAt.String(), cmd.CreatedAt.String())
+	description := highlight(models.SearchFieldDescription, cmd.Description, plain)
+	remote := ""
+	if remoteCommands[cmd] {
+		remote = remoteColor("[remote] ")
+	}
 	if len(cmd.Tags) != 0 {
-		tags := strings.Join(cmd.Tags, ", ")
-		return fmt.Sprintf("%s - %s (%s) %s", selector(cmd.Selector), descriptionColor(cmd.Description), tagsColor(tags), dateColor(timestamp))
+		return fmt.Sprintf("%s%s - %s (%s) %s", remote, selector(cmd.Selector), description, highlightTags(cmd.Tags), dateColor(timestamp))
 	} else {
-		return fmt.Sprintf("%s - %s %s", selector(cmd.Selector), descriptionColor(cmd.Description), dateColor(timestamp))
+		return fmt.Sprintf("%s%s - %s %s", remote, selector(cmd.Selector), description, dateColor(timestamp))
 	}
 }
 
@@ -75,7 +104,12 @@ func PrintCommand(header string, cmd *models.Command, sourceOnly bool) {
 		log.Fatal("Trying to display a nil command")
 	}
 
-	if sourceOnly {
+	if machineReadable() && !sourceOnly {
+		normalizeID(&cmd.Meta)
+		printStructured(cmd, [][]string{commandRow(cmd)})
+	} else if tmpl := templateFor(TemplateCommand); tmpl != nil && !sourceOnly {
+		tty.Print("%s\n", render(tmpl, cmd))
+	} else if sourceOnly {
 		tty.Print(cmd.Code + "\n")
 	} else {
 
@@ -90,33 +124,46 @@ func PrintCommand(header string, cmd *models.Command, sourceOnly bool) {
 			tty.Print("  Namespace: -\n")
 		}
 		tty.Print("  Space: %s \n", spaceColor(cmd.Selector.Space))
-		tty.Print("  Label: %s \n", labelColor(cmd.Label))
+		tty.Print("  Label: %s \n", highlight(models.SearchFieldLabel, cmd.Label, labelColor))
 		tty.Print("  Selector: %s \n", selector(cmd.Selector))
 		tty.Print("\n")
-		tty.Print("  Description: %s\n", descriptionColor(cmd.Description))
-		tty.Print("  URL: %s\n", urlColor(cmd.URL))
-		tty.Print("  Tags: %s\n", tagsColor(strings.Join(cmd.Tags, ", ")))
+		tty.Print("  Description: %s\n", highlight(models.SearchFieldDescription, cmd.Description, plain))
+		tty.Print("  URL: %s\n", highlight(models.SearchFieldURL, cmd.URL, urlColor))
+		tty.Print("  Tags: %s\n", highlightTags(cmd.Tags))
+		if variables := cmd.Variables(); len(variables) != 0 {
+			names := []string{}
+			for _, variable := range variables {
+				names = append(names, variable.String())
+			}
+			tty.Print("  Variables: %s\n", variableColor(strings.Join(names, ", ")))
+		}
 		tty.Print("\n")
 		tty.Print("  Created at: %s\n", dateColor(cmd.CreatedAt.String()))
 		tty.Print("  Updated at: %s\n", dateColor(cmd.UpdatedAt.String()))
 
-		tty.Print("\n%s\n\n%s\n\n", separatorColor("- - -"), cmd.Code)
+		tty.Print("\n%s\n\n%s\n\n", separatorColor("- - -"), highlight(models.SearchFieldCode, cmd.Code, plain))
 
 		printFooter(header)
 	}
 }
 
-func runFZFRemoteList(header string, commands []*models.Command, listingSort string) {
-	args := []string{"--ansi", "--exact", "--preview-window=down:30%:wrap", "--preview", "echo {} | cut -f1 -d' ' | xargs cbox cloud view"}
-	runFZF(header, commands, listingSort, args)
+func runFZFRemoteList(header string, commands []*models.Command, listingSort string, summary func(*models.Command) string) (*models.Command, string) {
+	args := []string{"--ansi", "--exact", "--preview-window=down:30%:wrap", "--preview", "cbox cloud view {1}"}
+	return runFZF(header, commands, listingSort, summary, args, fzfKeyCopy)
 }
 
-func runFZFList(header string, commands []*models.Command, listingSort string) {
-	args := []string{"--ansi", "--exact", "--preview-window=down:30%:wrap", "--preview", "echo {} | cut -f1 -d' ' | xargs cbox command view"}
-	runFZF(header, commands, listingSort, args)
+func runFZFList(header string, commands []*models.Command, listingSort string, summary func(*models.Command) string) (*models.Command, string) {
+	args := []string{"--ansi", "--exact", "--preview-window=down:30%:wrap", "--preview", "cbox command view {1}"}
+	return runFZF(header, commands, listingSort, summary, args, fzfKeyRun, fzfKeyCopy)
 }
 
-func runFZF(header string, commands []*models.Command, listingSort string, args []string) {
+// runFZF lets the user pick a command through fzf. Each line starts with a hidden field holding the
+// command's ID, so the summary displayed can be freely shaped
+func runFZF(header string, commands []*models.Command, listingSort string, summary func(*models.Command) string, args []string, keys ...string) (*models.Command, string) {
+	args = append(args, "--delimiter=\t", "--with-nth=2..")
+	if len(keys) != 0 {
+		args = append(args, "--expect="+strings.Join(keys, ","))
+	}
 	if header != "" {
 		args = append(args, "--header="+header)
 	}
@@ -132,7 +179,7 @@ func runFZF(header string, commands []*models.Command, listingSort string, args
 	fzfProcess.Stderr = os.Stderr
 
 	for _, cmd := range commands {
-		io.WriteString(stdin, commandSummary(cmd))
+		io.WriteString(stdin, cmd.ID+"\t"+strings.ReplaceAll(summary(cmd), "\n", " "))
 		io.WriteString(stdin, "\n")
 	}
 
@@ -146,17 +193,36 @@ func runFZF(header string, commands []*models.Command, listingSort string, args
 		} else if exitCode == 2 {
 			log.Fatalf("console: interactive mode: 'fzf' returned an internal error: %v", err)
 		}
-		return
+		return nil, ActionNone
+	}
+
+	// with --expect, fzf prints the key pressed (empty for enter) before the selected line
+	key := ""
+	line := string(out)
+	if len(keys) != 0 {
+		lines := strings.SplitN(line, "\n", 2)
+		key = lines[0]
+		line = ""
+		if len(lines) > 1 {
+			line = lines[1]
+		}
 	}
 
-	selector := strings.Split(string(out), " ")[0]
+	selector := strings.SplitN(line, "\t", 2)[0]
 
 	for _, cmd := range commands {
 		if cmd.ID == selector {
-			PrintCommand(selector, cmd, false)
-			break
+			switch key {
+			case fzfKeyRun:
+				return cmd, ActionRun
+			case fzfKeyCopy:
+				return cmd, ActionCopy
+			}
+			return cmd, ActionView
 		}
 	}
+
+	return nil, ActionNone
 }
 
 func staticCommandList(header string, commands []*models.Command) {
@@ -169,38 +235,239 @@ func staticCommandList(header string, commands []*models.Command) {
 	printFooter(header)
 }
 
-func PrintCommandList(header string, commands []*models.Command, listingMode string, listingSort string) {
+// runTUIList lets the user pick a command with the built-in picker, previewing each command in-process
+func runTUIList(header string, commands []*models.Command, listingSort string, summary func(*models.Command) string, bindings []tui.Binding) (*models.Command, string) {
+	items := []tui.Item{}
+	for _, cmd := range commands {
+		cmd := cmd
+		items = append(items, tui.Item{
+			Line:    summary(cmd),
+			Preview: func() string { return tty.Capture(func() { PrintCommand("", cmd, false) }) },
+		})
+	}
+
+	picked, action, err := tui.Pick(items, tui.Options{
+		Header:   header,
+		Bindings: bindings,
+		Reverse:  listingSort == "date",
+	})
+	if err != nil {
+		log.Fatalf("console: interactive mode: %v", err)
+	}
+	if len(picked) == 0 {
+		return nil, ActionNone
+	}
+
+	cmd := commands[picked[0]]
+	if action == tui.ActionPick {
+		return cmd, ActionView
+	}
+	return cmd, action
+}
+
+func templateCommandList(tmpl *template.Template, commands []*models.Command) {
+	for _, command := range commands {
+		tty.Print("%s\n", render(tmpl, command))
+	}
+}
+
+// PrintCommandList displays a list of commands. In interactive modes it returns
+// the command picked by the user and the action requested for it
+func PrintCommandList(header string, commands []*models.Command, listingMode string, listingSort string) (*models.Command, string) {
 	if len(commands) != 0 {
 		sortCommands(commands, listingSort)
 	}
 
+	if machineReadable() {
+		writeCommands(commands)
+		return nil, ActionNone
+	}
+
+	tmpl := templateFor(TemplateCommands)
+	summary := commandSummary
+	if tmpl != nil {
+		summary = func(cmd *models.Command) string { return render(tmpl, cmd) }
+	}
+
+	var command *models.Command
+	action := ActionNone
 	if listingMode == "interactive" {
-		runFZFList(header, commands, listingSort)
+		command, action = runFZFList(header, commands, listingSort, summary)
 	} else if listingMode == "interactive-remote" {
-		runFZFRemoteList(header, commands, listingSort)
+		command, action = runFZFRemoteList(header, commands, listingSort, summary)
+	} else if listingMode == "tui" {
+		command, action = runTUIList(header, commands, listingSort, summary, []tui.Binding{
+			{Key: tuiKeyRun, Action: ActionRun},
+			{Key: tuiKeyCopy, Action: ActionCopy},
+			{Key: tuiKeyEdit, Action: ActionEdit},
+			{Key: tuiKeyDelete, Action: ActionDelete},
+		})
+	} else if listingMode == "tui-remote" {
+		command, action = runTUIList(header, commands, listingSort, summary, []tui.Binding{
+			{Key: tuiKeyCopy, Action: ActionCopy},
+		})
+	} else if tmpl != nil {
+		templateCommandList(tmpl, commands)
+		return nil, ActionNone
 	} else {
 		staticCommandList(header, commands)
+		return nil, ActionNone
+	}
+
+	if action == ActionView {
+		PrintCommand(command.ID, command, false)
+	}
+	return command, action
+}
+
+// PickCommand lets the user pick a command, without any other action nor printing it, using fzf or the
+// built-in picker (the latter if asked for, or if fzf is not installed and the listing mode is not interactive)
+func PickCommand(header string, commands []*models.Command, listingMode string, listingSort string) *models.Command {
+	sortCommands(commands, listingSort)
+
+	if listingMode != "tui" {
+		if _, err := exec.LookPath("fzf"); err == nil || listingMode == "interactive" {
+			args := []string{"--ansi", "--exact", "--preview-window=down:30%:wrap", "--preview", "cbox command view {1}"}
+			command, _ := runFZF(header, commands, listingSort, commandSummary, args)
+			return command
+		}
+	}
+
+	command, _ := runTUIList(header, commands, listingSort, commandSummary, []tui.Binding{})
+	return command
+}
+
+// PrintSearchResults displays the commands found by a search. Static listings group them by space, while
+// interactive modes list all of them, returning the command picked by the user and the action requested
+func PrintSearchResults(header string, results []*models.SearchResults, listingMode string, listingSort string) (*models.Command, string) {
+	commands := []*models.Command{}
+	remoteCommands = map[*models.Command]bool{}
+	for _, group := range results {
+		sortCommands(group.Commands, listingSort)
+		commands = append(commands, group.Commands...)
+		for _, command := range group.Commands {
+			remoteCommands[command] = group.Remote
+		}
+	}
+
+	interactive := listingMode == "interactive" || listingMode == "tui"
+	if interactive || machineReadable() || templateFor(TemplateCommands) != nil {
+		return PrintCommandList(header, commands, listingMode, listingSort)
+	}
+
+	printHeader(header)
+	for _, group := range results {
+		count := fmt.Sprintf("%d commands", len(group.Commands))
+		if len(group.Commands) == 1 {
+			count = "1 command"
+		}
+		if group.Remote {
+			count = count + ", " + remoteColor("remote")
+		}
+		tty.Print("\n%s (%s)\n", selector(group.Space), count)
+		for _, command := range group.Commands {
+			tty.Print("%s %s\n", starColor("*"), commandSummary(command))
+		}
 	}
+	tty.Print("\n%d commands found in %d spaces\n", len(commands), len(results))
+	printFooter(header)
+
+	return nil, ActionNone
 }
 
-func PrintTag(tag string) {
-	tty.Print("%s %s\n", starColor("*"), tagsColor(tag))
+func PrintTags(tags []string) {
+	if machineReadable() {
+		rows := [][]string{}
+		for _, tag := range tags {
+			rows = append(rows, []string{tag})
+		}
+		printStructured(tags, rows)
+		return
+	}
+
+	tmpl := templateFor(TemplateTags)
+	for _, tag := range tags {
+		if tmpl != nil {
+			tty.Print("%s\n", render(tmpl, tag))
+		} else {
+			tty.Print("%s %s\n", starColor("*"), tagsColor(tag))
+		}
+	}
+}
+
+func PrintSpaces(spaces []*models.Space) {
+	if machineReadable() {
+		writeSpaces(spaces)
+		return
+	}
+
+	tmpl := templateFor(TemplateSpaces)
+	for _, space := range spaces {
+		if tmpl != nil {
+			tty.Print("%s\n", render(tmpl, space))
+		} else {
+			PrintSpace("", space)
+		}
+	}
 }
 
 func PrintSpace(header string, space *models.Space) {
+	if machineReadable() {
+		normalizeID(&space.Meta)
+		printStructured(space, [][]string{spaceRow(space)})
+		return
+	}
+
 	printHeader(header)
 	timestamp := fmt.Sprintf(timestampFormat, space.UpdatedAt.String(), space.CreatedAt.String())
 	tty.Print("%s - %s %s\n", selector(space.Selector), descriptionColor(space.Description), dateColor(timestamp))
 	printFooter(header)
 }
 
+// PrintTrashEntries lists the content of the trash. TSV columns: selector, kind, deleted at
+func PrintTrashEntries(entries []*models.TrashEntry) {
+	if machineReadable() {
+		rows := [][]string{}
+		for _, entry := range entries {
+			rows = append(rows, []string{entry.Selector.String(), entry.Kind(), tsvTime(entry.DeletedAt)})
+		}
+		printStructured(entries, rows)
+		return
+	}
+
+	if len(entries) == 0 {
+		tty.Print("Trash is empty\n")
+	}
+	for _, entry := range entries {
+		PrintTrashEntry(entry)
+	}
+}
+
+func PrintTrashEntry(entry *models.TrashEntry) {
+	content := entry.Kind()
+	if entry.Space != nil {
+		content = fmt.Sprintf("%s with %d commands", content, len(entry.Space.Entries))
+	}
+	deletedAt := fmt.Sprintf("(Deleted: %s)", entry.DeletedAt.String())
+	tty.Print("%s %s - %s %s\n", starColor("*"), selector(entry.Selector), content, dateColor(deletedAt))
+}
+
 func PrintSelector(header string, s *models.Selector) {
 	printHeader(header)
 	tty.Print("%s\n", selector(s))
 	printFooter(header)
 }
 
+type setting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
 func PrintSetting(config string, value string) {
+	if machineReadable() {
+		printStructured(setting{config, value}, [][]string{{config, value}})
+		return
+	}
 	tty.Print("%s -> %s\n", tty.ColorGreen(config), tty.ColorYellow(value))
 }
 
@@ -217,6 +484,10 @@ func printFooter(header string) {
 }
 
 func sortCommands(commands []*models.Command, listingSort string) {
+	if listingSort == SortRelevance {
+		return
+	}
+
 	sort.Slice(commands, func(i, j int) bool {
 		if commands[i] == nil || commands[j] == nil {
 			log.Fatal("Trying to sort a list of commands with nil entries")
@@ -0,0 +1,72 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+var syncKindColors = map[string]func(string) string{
+	models.SyncAdded:   diffAddedColor,
+	models.SyncChanged: tty.ColorYellow,
+	models.SyncDeleted: diffRemovedColor,
+}
+
+var syncResolutions = map[string]string{
+	models.MergeMine:   "kept the local version",
+	models.MergeTheirs: "kept the cloud version",
+	models.MergeEdited: "merged by hand",
+}
+
+// PrintSyncPlan displays the changes pushed to the cloud and pulled from it (or to be, if pending), the
+// commands whose changes on both sides were merged and how conflicts were solved
+func PrintSyncPlan(header string, plan *models.SyncPlan, pending bool) {
+	printHeader(header)
+
+	if plan.Empty() {
+		tty.Print("\n  Nothing to sync: both sides are up to date\n\n")
+		printFooter(header)
+		return
+	}
+
+	push, pull := "Pushed to the cloud", "Pulled from the cloud"
+	if pending {
+		push, pull = "To push to the cloud", "To pull from the cloud"
+	}
+	printSyncChanges(push, plan.Push)
+	printSyncChanges(pull, plan.Pull)
+
+	if len(plan.Merged) != 0 || len(plan.Conflicts) != 0 {
+		tty.Print("\n  %s\n", tty.ColorBoldYellow("Changed on both sides"))
+		for _, merge := range plan.Merged {
+			tty.Print("    %s %s: changes merged\n", tty.ColorYellow("~"), labelColor(merge.Label))
+		}
+		for _, conflict := range plan.Conflicts {
+			resolution, found := syncResolutions[conflict.Resolution]
+			if !found {
+				fields := []string{}
+				for _, field := range conflict.Merge.Conflicts {
+					fields = append(fields, field.Field)
+				}
+				resolution = fmt.Sprintf("conflicting %s to resolve", strings.Join(fields, ", "))
+			}
+			tty.Print("    %s %s: %s\n", tty.ColorYellow("!"), labelColor(conflict.Label), resolution)
+		}
+	}
+
+	tty.Print("\n")
+	printFooter(header)
+}
+
+func printSyncChanges(title string, changes []*models.SyncChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	tty.Print("\n  %s\n", tty.ColorBoldWhite(title))
+	for _, change := range changes {
+		tty.Print("    %s %s\n", syncKindColors[change.Kind](fmt.Sprintf("%-8s", change.Kind)), labelColor(change.Label))
+	}
+}
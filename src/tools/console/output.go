@@ -0,0 +1,131 @@
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/tty"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+	OutputTSV  = "tsv"
+)
+
+// OutputFormat sets how read commands print their results: colourised text for humans (default) or
+// a machine readable format. Machine readable outputs leave any other message out of the standard output
+var OutputFormat = ""
+
+func OutputFormats() []string {
+	return []string{OutputText, OutputJSON, OutputYAML, OutputTSV}
+}
+
+func CheckOutputFormat(format string) error {
+	for _, f := range OutputFormats() {
+		if format == f || format == "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format '%s' (supported: %s)", format, strings.Join(OutputFormats(), ", "))
+}
+
+func machineReadable() bool {
+	return OutputFormat != "" && OutputFormat != OutputText
+}
+
+// printMessage prints anything that is not a result, moved to the standard error for machine readable outputs
+func printMessage(format string, args ...interface{}) {
+	if machineReadable() {
+		tty.PrintError(format, args...)
+	} else {
+		tty.Print(format, args...)
+	}
+}
+
+// printStructured prints any value using the JSON tags of the models, or rows of columns for TSV
+func printStructured(value interface{}, rows [][]string) {
+	switch OutputFormat {
+	case OutputJSON:
+		raw, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			log.Fatalf("console: output: could not generate JSON: %v", err)
+		}
+		tty.Print("%s\n", string(raw))
+	case OutputYAML:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			log.Fatalf("console: output: could not generate YAML: %v", err)
+		}
+		// JSON is valid YAML: converting it keeps the JSON field names
+		var generic interface{}
+		if err := yaml.Unmarshal(raw, &generic); err != nil {
+			log.Fatalf("console: output: could not generate YAML: %v", err)
+		}
+		out, err := yaml.Marshal(generic)
+		if err != nil {
+			log.Fatalf("console: output: could not generate YAML: %v", err)
+		}
+		tty.Print("%s", string(out))
+	case OutputTSV:
+		for _, row := range rows {
+			for i, column := range row {
+				row[i] = tsvEscaper.Replace(column)
+			}
+			tty.Print("%s\n", strings.Join(row, "\t"))
+		}
+	}
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func tsvTime(t models.UnixTime) string {
+	return fmt.Sprint(time.Time(t).Unix())
+}
+
+// commandRow columns: selector, label, description, tags, url, code, created at, updated at
+func commandRow(cmd *models.Command) []string {
+	return []string{cmd.Selector.String(), cmd.Label, cmd.Description, strings.Join(cmd.Tags, ","), cmd.URL, cmd.Code, tsvTime(cmd.CreatedAt), tsvTime(cmd.UpdatedAt)}
+}
+
+// spaceRow columns: selector, label, description, number of commands, created at, updated at
+func spaceRow(space *models.Space) []string {
+	return []string{space.Selector.String(), space.Label, space.Description, fmt.Sprint(len(space.Entries)), tsvTime(space.CreatedAt), tsvTime(space.UpdatedAt)}
+}
+
+func writeCommands(commands []*models.Command) {
+	if commands == nil {
+		commands = []*models.Command{}
+	}
+	rows := [][]string{}
+	for _, cmd := range commands {
+		normalizeID(&cmd.Meta)
+		rows = append(rows, commandRow(cmd))
+	}
+	printStructured(commands, rows)
+}
+
+func writeSpaces(spaces []*models.Space) {
+	if spaces == nil {
+		spaces = []*models.Space{}
+	}
+	rows := [][]string{}
+	for _, space := range spaces {
+		normalizeID(&space.Meta)
+		rows = append(rows, spaceRow(space))
+	}
+	printStructured(spaces, rows)
+}
+
+// normalizeID fills the ID of models not stored yet (or coming from the cloud) from their selector
+func normalizeID(meta *models.Meta) {
+	if meta.ID == "" && meta.Selector != nil {
+		meta.ID = meta.Selector.String()
+	}
+}
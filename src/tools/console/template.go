@@ -0,0 +1,104 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+const (
+	TemplateCommands = "commands"
+	TemplateCommand  = "command"
+	TemplateSpaces   = "spaces"
+	TemplateTags     = "tags"
+)
+
+var (
+	// FormatTemplate is the template given with --format, used for any kind of result
+	FormatTemplate = ""
+	// DefaultTemplates are the templates configured for each kind of result, used if no --format is given
+	DefaultTemplates = map[string]string{}
+
+	templateEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+	templateColors = map[string]func(string) string{
+		"black":        tty.ColorBlack,
+		"red":          tty.ColorRed,
+		"green":        tty.ColorGreen,
+		"yellow":       tty.ColorYellow,
+		"blue":         tty.ColorBlue,
+		"magenta":      tty.ColorMagenta,
+		"cyan":         tty.ColorCyan,
+		"white":        tty.ColorWhite,
+		"bold-black":   tty.ColorBoldBlack,
+		"bold-red":     tty.ColorBoldRed,
+		"bold-green":   tty.ColorBoldGreen,
+		"bold-yellow":  tty.ColorBoldYellow,
+		"bold-blue":    tty.ColorBoldBlue,
+		"bold-magenta": tty.ColorBoldMagenta,
+		"bold-cyan":    tty.ColorBoldCyan,
+		"bold-white":   tty.ColorBoldWhite,
+	}
+
+	templateFuncs = template.FuncMap{
+		// join .Tags ","
+		"join": func(list []string, separator string) string {
+			return strings.Join(list, separator)
+		},
+		// .Label | color "blue"
+		"color": func(name string, text string) (string, error) {
+			color, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color '%s'", name)
+			}
+			return color(text), nil
+		},
+		// .Description | truncate 20
+		"truncate": func(length int, text string) string {
+			runes := []rune(text)
+			if len(runes) <= length {
+				return text
+			}
+			return string(runes[:length])
+		},
+		// .UpdatedAt | date "2006-01-02"
+		"date": func(layout string, t models.UnixTime) string {
+			return time.Time(t).Local().Format(layout)
+		},
+	}
+)
+
+func TemplateKinds() []string {
+	return []string{TemplateCommands, TemplateCommand, TemplateSpaces, TemplateTags}
+}
+
+// templateFor returns the template to render a kind of result, nil if results are printed as usual
+func templateFor(kind string) *template.Template {
+	text := FormatTemplate
+	if text == "" {
+		text = DefaultTemplates[kind]
+	}
+	if text == "" || machineReadable() {
+		return nil
+	}
+
+	tmpl, err := template.New(kind).Funcs(templateFuncs).Parse(templateEscapes.Replace(text))
+	if err != nil {
+		log.Fatalf("console: invalid format: %v", err)
+	}
+	return tmpl
+}
+
+func render(tmpl *template.Template, data interface{}) string {
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		log.Fatalf("console: format: %v", err)
+	}
+	return out.String()
+}
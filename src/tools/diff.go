@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DiffEqual   = ' '
+	DiffAdded   = '+'
+	DiffRemoved = '-'
+)
+
+type DiffLine struct {
+	Op   rune
+	Text string
+}
+
+// DiffLines compares two texts line by line, returning the lines of both in order, each one marked
+// as equal, added (only in b) or removed (only in a)
+func DiffLines(a string, b string) []DiffLine {
+	linesA := splitLines(a)
+	linesB := splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of linesA[i:] and linesB[j:]
+	lcs := make([][]int, len(linesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(linesB)+1)
+	}
+	for i := len(linesA) - 1; i >= 0; i-- {
+		for j := len(linesB) - 1; j >= 0; j-- {
+			if linesA[i] == linesB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := []DiffLine{}
+	i, j := 0, 0
+	for i < len(linesA) && j < len(linesB) {
+		if linesA[i] == linesB[j] {
+			diff = append(diff, DiffLine{DiffEqual, linesA[i]})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			diff = append(diff, DiffLine{DiffRemoved, linesA[i]})
+			i++
+		} else {
+			diff = append(diff, DiffLine{DiffAdded, linesB[j]})
+			j++
+		}
+	}
+	for ; i < len(linesA); i++ {
+		diff = append(diff, DiffLine{DiffRemoved, linesA[i]})
+	}
+	for ; j < len(linesB); j++ {
+		diff = append(diff, DiffLine{DiffAdded, linesB[j]})
+	}
+
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// UnifiedDiff compares two texts line by line, returning their differences in unified format: hunks of
+// changed lines surrounded by up to context equal ones, headed by the names given (empty if both texts
+// hold the same lines)
+func UnifiedDiff(a string, b string, nameA string, nameB string, context int) string {
+	diff := DiffLines(a, b)
+
+	changed := false
+	for _, line := range diff {
+		if line.Op != DiffEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+
+	for start := 0; start < len(diff); {
+		// find the next changed line, and extend the hunk while changes are closer than 2*context lines
+		first := start
+		for first < len(diff) && diff[first].Op == DiffEqual {
+			first++
+		}
+		if first == len(diff) {
+			break
+		}
+
+		last := first
+		for i := first; i < len(diff) && i <= last+2*context; i++ {
+			if diff[i].Op != DiffEqual {
+				last = i
+			}
+		}
+
+		from := max(first-context, start)
+		to := min(last+context+1, len(diff))
+
+		// line numbers (1-based) where the hunk starts on each text
+		lineA, lineB := 1, 1
+		for _, line := range diff[:from] {
+			if line.Op != DiffAdded {
+				lineA++
+			}
+			if line.Op != DiffRemoved {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		for _, line := range diff[from:to] {
+			if line.Op != DiffAdded {
+				countA++
+			}
+			if line.Op != DiffRemoved {
+				countB++
+			}
+		}
+		if countA == 0 {
+			lineA--
+		}
+		if countB == 0 {
+			lineB--
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(lineA, countA), hunkRange(lineB, countB))
+		for _, line := range diff[from:to] {
+			fmt.Fprintf(&out, "%c%s\n", line.Op, line.Text)
+		}
+
+		start = to
+	}
+
+	return out.String()
+}
+
+func hunkRange(line int, count int) string {
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
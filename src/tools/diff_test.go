@@ -0,0 +1,31 @@
+package tools
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\n"
+	b := "one\ntwo\n3\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+
+	expected := `--- a
++++ b
+@@ -2,3 +2,3 @@
+ two
+-three
++3
+ four
+@@ -9 +9,2 @@
+ nine
++ten
+`
+	if diff := UnifiedDiff(a, b, "a", "b", 1); diff != expected {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	if diff := UnifiedDiff("", "one\n", "a", "b", 3); diff != "--- a\n+++ b\n@@ -0,0 +1 @@\n+one\n" {
+		t.Errorf("unexpected diff from an empty text:\n%s", diff)
+	}
+
+	if diff := UnifiedDiff(a, a, "a", "b", 3); diff != "" {
+		t.Errorf("same texts should not differ:\n%s", diff)
+	}
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const defaultEditor = "vi"
+
+// ResolveEditor returns the editor set in $VISUAL or $EDITOR, which may include arguments (e.g.
+// 'code --wait')
+func ResolveEditor() string {
+	for _, variable := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(variable)); editor != "" {
+			return editor
+		}
+	}
+	return defaultEditor
+}
+
+// EditFile opens a file with the given editor, attaching it to the current terminal until it's closed
+func EditFile(editor string, path string) error {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return fmt.Errorf("no editor given")
+	}
+
+	process := exec.Command(args[0], append(args[1:], path)...)
+	process.Stdin = os.Stdin
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+
+	if err := process.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %v", editor, err)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package tools
 
 import (
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 )
 
 func CreateDirectoryIfNotExists(path string) bool {
@@ -25,3 +27,44 @@ func CreateFileIfNotExists(path string) bool {
 	}
 	return false
 }
+
+// WriteFileAtomic writes data into a temporary file in the same directory and renames it
+// over path once synced, so readers never find a partially written file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func CopyFile(src string, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(dst, data, info.Mode())
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyBonusConsecutive = 8
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusFirst       = 4
+	fuzzyPenaltyGap       = 1
+)
+
+// FuzzyMatch checks if every character in pattern appears in text in the same order (ignoring case),
+// returning a score that favours consecutive characters and matches at the start of words
+func FuzzyMatch(pattern string, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	needle := []rune(strings.ToLower(pattern))
+	haystack := []rune(text)
+
+	score := 0
+	matched := 0
+	last := -1
+	for i, r := range haystack {
+		if matched == len(needle) {
+			break
+		}
+		if unicode.ToLower(r) != needle[matched] {
+			continue
+		}
+
+		score += fuzzyScoreMatch
+		if i == 0 {
+			score += fuzzyBonusFirst
+		}
+		if i == 0 || !unicode.IsLetter(haystack[i-1]) && !unicode.IsDigit(haystack[i-1]) {
+			score += fuzzyBonusBoundary
+		}
+		if last != -1 {
+			if i == last+1 {
+				score += fuzzyBonusConsecutive
+			} else {
+				score -= (i - last - 1) * fuzzyPenaltyGap
+			}
+		}
+
+		last = i
+		matched++
+	}
+
+	if matched != len(needle) {
+		return 0, false
+	}
+	return score, true
+}
+
+// EditDistance returns the number of single character insertions, deletions, substitutions or
+// transpositions of adjacent characters needed to turn a into b
+func EditDistance(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
@@ -0,0 +1,53 @@
+package tools
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		text    string
+		match   bool
+	}{
+		{"", "anything", true},
+		{"dkr", "docker run", true},
+		{"DR", "docker run", true},
+		{"rd", "docker run", false},
+		{"dockers", "docker", false},
+	} {
+		if _, ok := FuzzyMatch(test.pattern, test.text); ok != test.match {
+			t.Errorf("FuzzyMatch(%q, %q): expected match %v", test.pattern, test.text, test.match)
+		}
+	}
+}
+
+func TestFuzzyMatchRanking(t *testing.T) {
+	consecutive, _ := FuzzyMatch("run", "docker run")
+	scattered, _ := FuzzyMatch("run", "rebuild unused nodes")
+	if consecutive <= scattered {
+		t.Errorf("consecutive match (%d) should rank higher than a scattered one (%d)", consecutive, scattered)
+	}
+
+	boundary, _ := FuzzyMatch("log", "git log")
+	middle, _ := FuzzyMatch("log", "catalogue")
+	if boundary <= middle {
+		t.Errorf("match at the start of a word (%d) should rank higher than one in the middle (%d)", boundary, middle)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, test := range []struct {
+		a, b     string
+		distance int
+	}{
+		{"kubectl", "kubectl", 0},
+		{"kubctl", "kubectl", 1},
+		{"kubetcl", "kubectl", 1},
+		{"dokcer", "docker", 1},
+		{"git", "svn", 3},
+		{"", "abc", 3},
+	} {
+		if distance := EditDistance(test.a, test.b); distance != test.distance {
+			t.Errorf("EditDistance(%q, %q): expected %d, got %d", test.a, test.b, test.distance, distance)
+		}
+	}
+}
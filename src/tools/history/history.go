@@ -0,0 +1,232 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+
+	// zsh escapes bytes with special meaning in its history file with this one, xor-ing the next one with 0x20
+	zshMeta = 0x83
+)
+
+var (
+	bashTimestampRegexp = regexp.MustCompile(`^#(\d+)$`)
+	zshExtendedRegexp   = regexp.MustCompile(`^: *(\d+):\d+;(.*)$`)
+)
+
+// Entry is a command found in a shell history file. Timestamp is zero if the file does not record it
+type Entry struct {
+	Command   string
+	Timestamp time.Time
+}
+
+func Shells() []string {
+	return []string{ShellBash, ShellZsh, ShellFish}
+}
+
+// DefaultFile returns where the history of a shell is stored by default
+func DefaultFile(shell string, home string) (string, error) {
+	switch shell {
+	case ShellBash:
+		if histFile := os.Getenv("HISTFILE"); histFile != "" && strings.HasSuffix(os.Getenv("SHELL"), ShellBash) {
+			return histFile, nil
+		}
+		return path.Join(home, ".bash_history"), nil
+	case ShellZsh:
+		if histFile := os.Getenv("HISTFILE"); histFile != "" && strings.HasSuffix(os.Getenv("SHELL"), ShellZsh) {
+			return histFile, nil
+		}
+		return path.Join(home, ".zsh_history"), nil
+	case ShellFish:
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = path.Join(home, ".local", "share")
+		}
+		return path.Join(dataHome, "fish", "fish_history"), nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s' (supported: %s)", shell, strings.Join(Shells(), ", "))
+}
+
+// Parse reads the entries of a history file written by a shell, in the order they were recorded
+func Parse(shell string, r io.Reader) ([]Entry, error) {
+	switch shell {
+	case ShellBash:
+		return parseBash(r)
+	case ShellZsh:
+		return parseZsh(r)
+	case ShellFish:
+		return parseFish(r)
+	}
+	return nil, fmt.Errorf("unsupported shell '%s' (supported: %s)", shell, strings.Join(Shells(), ", "))
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return scanner
+}
+
+func parseUnixTimestamp(str string) time.Time {
+	ts, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
+
+// parseBash reads bash history files, where each line is a command optionally preceded by a '#<timestamp>'
+// line when HISTTIMEFORMAT is set
+func parseBash(r io.Reader) ([]Entry, error) {
+	entries := []Entry{}
+	timestamp := time.Time{}
+
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := bashTimestampRegexp.FindStringSubmatch(line); m != nil {
+			timestamp = parseUnixTimestamp(m[1])
+			continue
+		}
+		entries = append(entries, Entry{Command: line, Timestamp: timestamp})
+		timestamp = time.Time{}
+	}
+
+	return entries, scanner.Err()
+}
+
+// parseZsh reads zsh history files, both plain and with EXTENDED_HISTORY (': <timestamp>:<elapsed>;<command>').
+// Lines of multi-line commands end with a backslash
+func parseZsh(r io.Reader) ([]Entry, error) {
+	entries := []Entry{}
+
+	var current *Entry
+	continues := false
+
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		line := unmetafy(scanner.Bytes())
+
+		if continues && current != nil {
+			current.Command += "\n" + line
+		} else {
+			entry := Entry{Command: line}
+			if m := zshExtendedRegexp.FindStringSubmatch(line); m != nil {
+				entry.Timestamp = parseUnixTimestamp(m[1])
+				entry.Command = m[2]
+			}
+			entries = append(entries, entry)
+			current = &entries[len(entries)-1]
+		}
+
+		continues = strings.HasSuffix(line, "\\")
+		if continues {
+			current.Command = strings.TrimSuffix(current.Command, "\\")
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+func unmetafy(line []byte) string {
+	if bytes.IndexByte(line, zshMeta) == -1 {
+		return string(line)
+	}
+
+	result := make([]byte, 0, len(line))
+	for i := 0; i < len(line); i++ {
+		if line[i] == zshMeta && i+1 < len(line) {
+			i++
+			result = append(result, line[i]^0x20)
+		} else {
+			result = append(result, line[i])
+		}
+	}
+	return string(result)
+}
+
+// parseFish reads fish history files, a YAML-like list of '- cmd: <command>' items with a 'when: <timestamp>'
+// property (among others, ignored). Commands are escaped: '\n' for new lines and '\\' for backslashes
+func parseFish(r io.Reader) ([]Entry, error) {
+	entries := []Entry{}
+
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "- cmd: ") {
+			entries = append(entries, Entry{Command: unescapeFish(strings.TrimPrefix(line, "- cmd: "))})
+		} else if strings.HasPrefix(line, "  when: ") && len(entries) != 0 {
+			entries[len(entries)-1].Timestamp = parseUnixTimestamp(strings.TrimSpace(strings.TrimPrefix(line, "  when: ")))
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+func unescapeFish(str string) string {
+	var sb strings.Builder
+	for i := 0; i < len(str); i++ {
+		if str[i] == '\\' && i+1 < len(str) {
+			switch str[i+1] {
+			case 'n':
+				sb.WriteByte('\n')
+				i++
+				continue
+			case '\\':
+				sb.WriteByte('\\')
+				i++
+				continue
+			}
+		}
+		sb.WriteByte(str[i])
+	}
+	return sb.String()
+}
+
+// Dedup removes empty and repeated commands, keeping the most recent use of each one. The result is sorted
+// from the most recent to the oldest entry
+func Dedup(entries []Entry) []Entry {
+	seen := make(map[string]bool)
+	result := []Entry{}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		entry.Command = strings.TrimSpace(entry.Command)
+		if entry.Command == "" || seen[entry.Command] {
+			continue
+		}
+		seen[entry.Command] = true
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// Filter keeps the entries recorded after since (if not zero) and matching the grep expression (if not nil).
+// Entries without timestamp are discarded when filtering by date
+func Filter(entries []Entry, since time.Time, grep *regexp.Regexp) []Entry {
+	result := []Entry{}
+	for _, entry := range entries {
+		if !since.IsZero() && entry.Timestamp.Before(since) {
+			continue
+		}
+		if grep != nil && !grep.MatchString(entry.Command) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package history_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dplabs/cbox/src/tools/history"
+)
+
+func assertCommands(t *testing.T, entries []history.Entry, expected ...string) {
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expected), len(entries), entries)
+	}
+	for i, entry := range entries {
+		if entry.Command != expected[i] {
+			t.Errorf("entry %d: expected '%s', got '%s'", i, expected[i], entry.Command)
+		}
+	}
+}
+
+func TestParseBash(t *testing.T) {
+	entries, err := history.Parse(history.ShellBash, strings.NewReader("ls -la\n#1600000000\ngit status\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCommands(t, entries, "ls -la", "git status")
+	if !entries[0].Timestamp.IsZero() || entries[1].Timestamp.Unix() != 1600000000 {
+		t.Errorf("unexpected timestamps: %+v", entries)
+	}
+}
+
+func TestParseZsh(t *testing.T) {
+	file := ": 1600000000:0;ls -la\n: 1600000010:3;for f in *; do\\\necho $f\\\ndone\nplain command\n"
+	file += ": 1600000020:0;echo caf\x83\xe3\n"
+
+	entries, err := history.Parse(history.ShellZsh, strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCommands(t, entries, "ls -la", "for f in *; do\necho $f\ndone", "plain command", "echo caf\xc3")
+	if entries[1].Timestamp.Unix() != 1600000010 || !entries[2].Timestamp.IsZero() {
+		t.Errorf("unexpected timestamps: %+v", entries)
+	}
+}
+
+func TestParseFish(t *testing.T) {
+	file := "- cmd: ls -la\n  when: 1600000000\n- cmd: echo a\\nb \\\\ c\n  when: 1600000010\n  paths:\n    - a\n"
+
+	entries, err := history.Parse(history.ShellFish, strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCommands(t, entries, "ls -la", "echo a\nb \\ c")
+	if entries[1].Timestamp.Unix() != 1600000010 {
+		t.Errorf("unexpected timestamps: %+v", entries)
+	}
+}
+
+func TestDedupAndFilter(t *testing.T) {
+	entries := []history.Entry{
+		{Command: "ls", Timestamp: time.Unix(100, 0)},
+		{Command: "git status", Timestamp: time.Unix(200, 0)},
+		{Command: "  ", Timestamp: time.Unix(250, 0)},
+		{Command: "ls", Timestamp: time.Unix(300, 0)},
+		{Command: "git log"},
+	}
+
+	deduped := history.Dedup(entries)
+	assertCommands(t, deduped, "git log", "ls", "git status")
+
+	assertCommands(t, history.Filter(deduped, time.Unix(150, 0), nil), "ls", "git status")
+	assertCommands(t, history.Filter(deduped, time.Time{}, regexp.MustCompile("^git")), "git log", "git status")
+}
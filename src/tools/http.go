@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// HTTPOptions configure how HTTP clients connect to servers
+type HTTPOptions struct {
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+	// Proxy is the URL of the proxy to use (by default, the one in HTTPS_PROXY or HTTP_PROXY, unless
+	// excluded by NO_PROXY)
+	Proxy string
+	// CAFile holds PEM certificates of authorities to trust, besides the ones of the system
+	CAFile string
+}
+
+// NewHTTPClient returns an HTTP client which gives up connecting after the connect timeout, and waiting
+// for the response after the read timeout
+func NewHTTPClient(options HTTPOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	dialer := net.Dialer{Timeout: options.ConnectTimeout, KeepAlive: 30 * time.Second}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = options.ConnectTimeout
+	transport.ResponseHeaderTimeout = options.ReadTimeout
+
+	if options.Proxy != "" {
+		proxy, err := url.Parse(options.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL '%s'", options.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	if options.CAFile != "" {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA file: %v", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file '%s'", options.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	client := http.Client{Transport: transport}
+	if options.ConnectTimeout != 0 && options.ReadTimeout != 0 {
+		// so reading a stalled body doesn't hang either
+		client.Timeout = options.ConnectTimeout + options.ReadTimeout
+	}
+	return &client, nil
+}
+
+// RetryWait returns how long to wait before retrying a request for the nth time (starting at 0): what
+// the server asked for in its Retry-After header (seconds or date) if any, or an exponential backoff from
+// base with jitter otherwise (between half and the whole of base*2^n). Never longer than limit
+func RetryWait(attempt int, base time.Duration, limit time.Duration, retryAfter string) time.Duration {
+	wait := time.Duration(-1)
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		wait = max(time.Until(date), 0)
+	}
+
+	if wait < 0 {
+		backoff := base << attempt
+		if backoff < base || backoff > limit {
+			backoff = limit
+		}
+		wait = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	}
+
+	return min(wait, limit)
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryWait(t *testing.T) {
+	for attempt := 0; attempt < 5; attempt++ {
+		backoff := time.Second << attempt
+		if wait := RetryWait(attempt, time.Second, time.Minute, ""); wait < backoff/2 || wait > backoff {
+			t.Errorf("attempt %d: expected a wait between %v and %v, got %v", attempt, backoff/2, backoff, wait)
+		}
+	}
+
+	if wait := RetryWait(10, time.Second, 30*time.Second, ""); wait > 30*time.Second {
+		t.Errorf("backoff should be limited, got %v", wait)
+	}
+	if wait := RetryWait(0, time.Second, time.Minute, "7"); wait != 7*time.Second {
+		t.Errorf("Retry-After in seconds should be honoured, got %v", wait)
+	}
+	if wait := RetryWait(0, time.Second, time.Minute, "3600"); wait != time.Minute {
+		t.Errorf("Retry-After should be limited, got %v", wait)
+	}
+
+	date := time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat)
+	if wait := RetryWait(0, time.Second, time.Minute, date); wait < 18*time.Second || wait > 20*time.Second {
+		t.Errorf("Retry-After date should be honoured, got %v", wait)
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	if _, err := NewHTTPClient(HTTPOptions{Proxy: "not a url"}); err == nil {
+		t.Errorf("invalid proxy should be rejected")
+	}
+	if _, err := NewHTTPClient(HTTPOptions{CAFile: "http_test.go"}); err == nil {
+		t.Errorf("CA file without certificates should be rejected")
+	}
+
+	client, err := NewHTTPClient(HTTPOptions{ConnectTimeout: time.Second, ReadTimeout: 2 * time.Second, Proxy: "http://proxy:3128"})
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	request, _ := http.NewRequest("GET", "https://api.cbox.dplabs.io", nil)
+	proxy, err := client.Transport.(*http.Transport).Proxy(request)
+	if err != nil || proxy == nil || proxy.Host != "proxy:3128" {
+		t.Errorf("proxy given should be used, got %v (%v)", proxy, err)
+	}
+	if client.Timeout != 3*time.Second {
+		t.Errorf("unexpected client timeout %v", client.Timeout)
+	}
+}
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	generatedLabelMaxLength = 40
+	generatedLabelDefault   = "command"
+)
+
+var labelInvalidCharsRegexp = regexp.MustCompile("[^a-z0-9]+")
+
+// GenerateLabel builds a valid label (lowercase letters, numbers and dashes) out of any text, adding a
+// numeric suffix if needed so it's not taken already
+func GenerateLabel(text string, taken func(label string) bool) string {
+	label := labelInvalidCharsRegexp.ReplaceAllString(strings.ToLower(text), "-")
+	if len(label) > generatedLabelMaxLength {
+		label = label[:generatedLabelMaxLength]
+	}
+	label = strings.Trim(label, "-")
+	if label == "" {
+		label = generatedLabelDefault
+	}
+
+	unique := label
+	for i := 2; taken(unique); i++ {
+		unique = fmt.Sprintf("%s-%d", label, i)
+	}
+	return unique
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const defaultShell = "/bin/sh"
+
+func ResolveShell(shell string) string {
+	if shell != "" {
+		return shell
+	}
+	if shell = os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return defaultShell
+}
+
+// RunSnippet executes code using the given shell, attaching it to the current
+// terminal, and returns the exit code of the snippet (128 plus the signal number
+// if it was killed by a signal, like shells do)
+func RunSnippet(shell string, code string) (int, error) {
+	process := exec.Command(shell, "-c", code)
+	process.Stdin = os.Stdin
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+
+	err := process.Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+				return 128 + int(status.Signal()), nil
+			}
+			return exitErr.ExitCode(), nil
+		}
+		return -1, err
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,13 @@
+package tools
+
+import "testing"
+
+func TestRunSnippetExitCode(t *testing.T) {
+	if exitCode, err := RunSnippet(defaultShell, "exit 3"); err != nil || exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d (%v)", exitCode, err)
+	}
+
+	if exitCode, err := RunSnippet(defaultShell, "kill -TERM $$"); err != nil || exitCode != 143 {
+		t.Errorf("snippets killed by a signal should exit with 128 plus its number, got %d (%v)", exitCode, err)
+	}
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultWidgetKey = "ctrl-g"
+
+// shell functions calling 'cbox pick' and inserting the code printed at the cursor position of the
+// command line. %[1]s is replaced with the key binding and %[2]s with the arguments for 'cbox pick'
+var shellWidgets = map[string]string{
+	"bash": `__cbox_widget() {
+  local code
+  code="$(cbox pick%[2]s < /dev/tty)" || return
+  READLINE_LINE="${READLINE_LINE:0:$READLINE_POINT}${code}${READLINE_LINE:$READLINE_POINT}"
+  READLINE_POINT=$(( READLINE_POINT + ${#code} ))
+}
+bind -m emacs-standard -x '"%[1]s": __cbox_widget'
+bind -m vi-insert -x '"%[1]s": __cbox_widget'
+`,
+	"zsh": `__cbox_widget() {
+  local code
+  code="$(cbox pick%[2]s < /dev/tty)"
+  local ret=$?
+  if [[ $ret -eq 0 && -n "$code" ]]; then
+    LBUFFER="${LBUFFER}${code}"
+  fi
+  zle reset-prompt
+  return $ret
+}
+zle -N __cbox_widget
+bindkey -M emacs '%[1]s' __cbox_widget
+bindkey -M viins '%[1]s' __cbox_widget
+`,
+	"fish": `function __cbox_widget
+  set -l code (cbox pick%[2]s < /dev/tty | string collect)
+  if test -n "$code"
+    commandline -i -- $code
+  end
+  commandline -f repaint
+end
+bind %[1]s __cbox_widget
+if bind -M insert > /dev/null 2>&1
+  bind -M insert %[1]s __cbox_widget
+end
+`,
+}
+
+// ShellWidgetShells returns the shells a widget can be generated for
+func ShellWidgetShells() []string {
+	return []string{"bash", "zsh", "fish"}
+}
+
+// ShellWidget generates the script binding a key (like 'ctrl-g') to a widget that lets the user pick a
+// command and inserts its code in the command line, instead of running it. Args are passed to 'cbox pick'
+func ShellWidget(shell string, key string, args ...string) (string, error) {
+	widget, found := shellWidgets[shell]
+	if !found {
+		return "", fmt.Errorf("shell '%s' not supported (supported ones: %s)", shell, strings.Join(ShellWidgetShells(), ", "))
+	}
+
+	binding, err := shellKeyBinding(shell, key)
+	if err != nil {
+		return "", err
+	}
+
+	quoted := ""
+	for _, arg := range args {
+		quoted += " '" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	if shell == "fish" {
+		quoted = strings.ReplaceAll(quoted, `'\''`, `\'`)
+	}
+
+	return fmt.Sprintf(widget, binding, quoted), nil
+}
+
+// shellKeyBinding translates a key like 'ctrl-g' (or 'alt-g') to the notation of each shell
+func shellKeyBinding(shell string, key string) (string, error) {
+	parts := strings.SplitN(strings.ToLower(key), "-", 2)
+	if len(parts) != 2 || len(parts[1]) != 1 || parts[1][0] < 'a' || parts[1][0] > 'z' {
+		return "", fmt.Errorf("invalid key '%s' (e.g. ctrl-g or alt-g)", key)
+	}
+	letter := parts[1]
+
+	switch parts[0] {
+	case "ctrl":
+		switch shell {
+		case "bash":
+			return `\C-` + letter, nil
+		case "zsh":
+			return "^" + strings.ToUpper(letter), nil
+		case "fish":
+			return `\c` + letter, nil
+		}
+	case "alt":
+		switch shell {
+		case "bash":
+			return `\e` + letter, nil
+		case "zsh":
+			return "^[" + letter, nil
+		case "fish":
+			return `\e` + letter, nil
+		}
+	}
+	return "", fmt.Errorf("invalid key '%s' (e.g. ctrl-g or alt-g)", key)
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellWidget(t *testing.T) {
+	for _, test := range []struct {
+		shell   string
+		key     string
+		binding string
+	}{
+		{"bash", "ctrl-g", `"\C-g": __cbox_widget`},
+		{"zsh", "ctrl-g", `bindkey -M emacs '^G' __cbox_widget`},
+		{"fish", "alt-k", `bind \ek __cbox_widget`},
+	} {
+		widget, err := ShellWidget(test.shell, test.key)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.shell, err)
+			continue
+		}
+		if !strings.Contains(widget, test.binding) {
+			t.Errorf("%s: binding %q not found in widget:\n%s", test.shell, test.binding, widget)
+		}
+	}
+
+	widget, _ := ShellWidget("zsh", DefaultWidgetKey, "@it's")
+	if !strings.Contains(widget, `cbox pick '@it'\''s' < /dev/tty`) {
+		t.Errorf("arguments not quoted in widget:\n%s", widget)
+	}
+
+	if _, err := ShellWidget("tcsh", DefaultWidgetKey); err == nil {
+		t.Errorf("unsupported shell accepted")
+	}
+	if _, err := ShellWidget("bash", "ctrl-12"); err == nil {
+		t.Errorf("invalid key accepted")
+	}
+}
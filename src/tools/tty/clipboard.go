@@ -0,0 +1,83 @@
+package tty
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ClipboardOSC52 is the provider used when copying through the terminal itself, with an OSC 52 escape
+// sequence, which also works over SSH in the terminals supporting it
+const ClipboardOSC52 = "osc52"
+
+var (
+	MockedClipboard = ""
+
+	// commands able to copy their standard input into the clipboard, and the environment variable telling
+	// they can be used (if any)
+	clipboardProviders = []struct {
+		command  []string
+		variable string
+	}{
+		{[]string{"wl-copy"}, "WAYLAND_DISPLAY"},
+		{[]string{"xclip", "-selection", "clipboard"}, "DISPLAY"},
+		{[]string{"xsel", "--clipboard", "--input"}, "DISPLAY"},
+		{[]string{"pbcopy"}, ""},
+		{[]string{"clip.exe"}, ""},
+	}
+)
+
+// Copy puts text into the clipboard, returning the provider used. Over SSH, the terminal is used
+// (OSC 52) so the text reaches the local clipboard instead of the remote one
+func Copy(text string) (string, error) {
+	if MockTTY {
+		MockedClipboard = text
+		return "mock", nil
+	}
+
+	remote := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+	if !remote {
+		for _, provider := range clipboardProviders {
+			if provider.variable != "" && os.Getenv(provider.variable) == "" {
+				continue
+			}
+			if _, err := exec.LookPath(provider.command[0]); err != nil {
+				continue
+			}
+
+			process := exec.Command(provider.command[0], provider.command[1:]...)
+			process.Stdin = strings.NewReader(text)
+			if err := process.Run(); err == nil {
+				return provider.command[0], nil
+			}
+		}
+	}
+
+	if !isTerminal(os.Stderr) {
+		return "", fmt.Errorf("no clipboard available")
+	}
+	if err := copyOSC52(text); err != nil {
+		return "", err
+	}
+	return ClipboardOSC52, nil
+}
+
+func copyOSC52(text string) error {
+	sequence := fmt.Sprintf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	if os.Getenv("TMUX") != "" {
+		// tmux only forwards escape sequences to the terminal when wrapped
+		sequence = fmt.Sprintf("\x1bPtmux;\x1b%s\x1b\\", sequence)
+	}
+
+	if _, err := fmt.Fprint(os.Stderr, sequence); err != nil {
+		return fmt.Errorf("could not write to the terminal: %v", err)
+	}
+	return nil
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
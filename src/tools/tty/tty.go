@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 
 	survey "gopkg.in/AlecAivazis/survey.v1"
 	surveyCore "gopkg.in/AlecAivazis/survey.v1/core"
@@ -16,6 +17,8 @@ var (
 	MockTTY       = false
 	MockedOutput  = ""
 	MockedInput   = []string{}
+
+	captured *strings.Builder
 )
 
 func init() {
@@ -38,14 +41,26 @@ func print(w io.Writer, format string, args ...interface{}) {
 		} else {
 			nl = fmt.Sprintf(format)
 		}
-		if MockTTY {
+		if captured != nil {
+			captured.WriteString(nl)
+		} else if MockTTY {
 			MockedOutput = MockedOutput + nl
 		} else {
-			fmt.Fprintf(w, nl)
+			fmt.Fprint(w, nl)
 		}
 	}
 }
 
+// Capture returns everything printed while running fn, instead of writing it out
+func Capture(fn func()) string {
+	previous := captured
+	captured = &strings.Builder{}
+	defer func() { captured = previous }()
+
+	fn()
+	return captured.String()
+}
+
 func Read(label string, help string, multiline bool) (string, error) {
 	if MockTTY {
 		if len(MockedInput) > 0 {
@@ -76,6 +91,21 @@ func Read(label string, help string, multiline bool) (string, error) {
 	return value, err
 }
 
+// ReadAll returns everything piped into the standard input
+func ReadAll() (string, error) {
+	if MockTTY {
+		if len(MockedInput) > 0 {
+			value := MockedInput[0]
+			MockedInput = MockedInput[1:]
+			return value, nil
+		}
+		log.Fatalf("input mocked but not enough values provided for standard input - Mocked output until this moment: \n %s", MockedOutput)
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	return string(data), err
+}
+
 func Confirm(label string) bool {
 	if SkipQuestions {
 		return true
@@ -100,6 +130,39 @@ func Confirm(label string) bool {
 	return response
 }
 
+// Choose asks to pick one of the options given, returning defaultOption when questions are skipped (or an
+// empty string if the question is interrupted)
+func Choose(label string, options []string, defaultOption string) string {
+	if SkipQuestions {
+		return defaultOption
+	} else if MockTTY {
+		if len(MockedInput) > 0 {
+			value := MockedInput[0]
+			MockedInput = MockedInput[1:]
+			for _, option := range options {
+				if value == option {
+					return value
+				}
+			}
+			log.Fatalf("input mocked with an invalid option '%s' for choice (label used: '%s', options: %v)", value, label, options)
+		}
+		log.Fatalf("input mocked but not enough values provided for choice (label used: '%s')", label)
+	}
+
+	response := ""
+
+	prompt := &survey.Select{
+		Message: label,
+		Options: options,
+		Default: defaultOption,
+	}
+	survey.AskOne(prompt, &response, nil)
+
+	Print("\n")
+
+	return response
+}
+
 func Debug(msg string) {
 	Print("%s\n", ColorBoldBlack(msg))
 }
@@ -0,0 +1,432 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dplabs/cbox/src/tools"
+	"github.com/dplabs/cbox/src/tools/tty"
+)
+
+const (
+	// ActionPick is the action returned when an item is picked with enter
+	ActionPick = ""
+
+	keyEnter     = "enter"
+	keyCancel    = "cancel"
+	keyUp        = "up"
+	keyDown      = "down"
+	keyPageUp    = "page-up"
+	keyPageDown  = "page-down"
+	keyBackspace = "backspace"
+	keyClear     = "clear"
+	keyWord      = "delete-word"
+	keyToggle    = "toggle"
+	keyIgnore    = "ignore"
+
+	tabWidth = 4
+)
+
+var ansiEscapeRegexp = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+
+// Item is each of the entries offered by the picker
+type Item struct {
+	// Line is the text displayed in the list, which may contain colors
+	Line string
+	// Preview returns the text displayed in the preview pane for the item
+	Preview func() string
+}
+
+// Binding is a key (e.g. 'ctrl-r') that picks the current item requesting some action
+type Binding struct {
+	Key    string
+	Action string
+}
+
+// Options tune how the picker behaves
+type Options struct {
+	Header   string
+	Bindings []Binding
+	// Multi allows to select several items with TAB
+	Multi bool
+	// Reverse lists items the other way round
+	Reverse bool
+}
+
+// Pick lets the user choose items in a full screen picker, filtering them fuzzily as the user types. It
+// returns the indexes of the items picked (none if cancelled) and the action requested for them
+func Pick(items []Item, options Options) ([]int, string, error) {
+	term, err := openTerminal()
+	if err != nil {
+		return nil, ActionPick, fmt.Errorf("could not set up terminal: %v", err)
+	}
+	defer term.close()
+
+	picker := newPicker(items, options)
+	return picker.run(bufio.NewReader(term.in), term.out, term.size)
+}
+
+type picker struct {
+	items    []Item
+	options  Options
+	keys     map[string]string
+	previews map[int]string
+
+	query    []rune
+	matches  []int
+	cursor   int
+	offset   int
+	selected map[int]bool
+}
+
+func newPicker(items []Item, options Options) *picker {
+	keys := map[string]string{}
+	for _, binding := range options.Bindings {
+		keys[binding.Key] = binding.Action
+	}
+
+	p := &picker{
+		items:    items,
+		options:  options,
+		keys:     keys,
+		previews: map[int]string{},
+		selected: map[int]bool{},
+	}
+	p.filter()
+	return p
+}
+
+func (p *picker) run(in *bufio.Reader, out io.Writer, size func() (int, int)) ([]int, string, error) {
+	for {
+		width, height := size()
+		p.draw(out, width, height)
+
+		key, r, err := readKey(in)
+		if err == io.EOF {
+			return nil, ActionPick, nil
+		} else if err != nil {
+			return nil, ActionPick, err
+		}
+
+		listHeight := p.listHeight(height)
+
+		switch key {
+		case keyCancel:
+			return nil, ActionPick, nil
+		case keyEnter:
+			return p.picked(), ActionPick, nil
+		case keyUp:
+			p.move(-1, listHeight)
+		case keyDown:
+			p.move(1, listHeight)
+		case keyPageUp:
+			p.move(-listHeight, listHeight)
+		case keyPageDown:
+			p.move(listHeight, listHeight)
+		case keyBackspace:
+			if len(p.query) != 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.filter()
+			}
+		case keyClear:
+			p.query = []rune{}
+			p.filter()
+		case keyWord:
+			query := strings.TrimRight(string(p.query), " ")
+			p.query = []rune(query[:strings.LastIndex(query, " ")+1])
+			p.filter()
+		case keyToggle:
+			if p.options.Multi && len(p.matches) != 0 {
+				index := p.matches[p.cursor]
+				p.selected[index] = !p.selected[index]
+				p.move(1, listHeight)
+			}
+		case keyIgnore:
+		case "":
+			p.query = append(p.query, r)
+			p.filter()
+		default:
+			if action, found := p.keys[key]; found {
+				return p.picked(), action, nil
+			}
+		}
+	}
+}
+
+// picked returns the items selected with TAB or, if none, the one under the cursor
+func (p *picker) picked() []int {
+	picked := []int{}
+	for index := range p.items {
+		if p.selected[index] {
+			picked = append(picked, index)
+		}
+	}
+	if len(picked) == 0 && len(p.matches) != 0 {
+		picked = append(picked, p.matches[p.cursor])
+	}
+	return picked
+}
+
+// filter keeps the items matching every word of the query, best matches first
+func (p *picker) filter() {
+	terms := strings.Fields(string(p.query))
+
+	type match struct {
+		index int
+		score int
+	}
+	matches := []match{}
+	for i := range p.items {
+		index := i
+		if p.options.Reverse {
+			index = len(p.items) - 1 - i
+		}
+
+		line := ansiEscapeRegexp.ReplaceAllString(p.items[index].Line, "")
+		total := 0
+		found := true
+		for _, term := range terms {
+			score, ok := tools.FuzzyMatch(term, line)
+			if !ok {
+				found = false
+				break
+			}
+			total += score
+		}
+		if found {
+			matches = append(matches, match{index, total})
+		}
+	}
+
+	if len(terms) != 0 {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	}
+
+	p.matches = []int{}
+	for _, m := range matches {
+		p.matches = append(p.matches, m.index)
+	}
+	p.cursor = 0
+	p.offset = 0
+}
+
+func (p *picker) move(delta int, listHeight int) {
+	p.cursor += delta
+	if p.cursor >= len(p.matches) {
+		p.cursor = len(p.matches) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+listHeight {
+		p.offset = p.cursor - listHeight + 1
+	}
+}
+
+// listHeight splits the screen between the list and the preview pane, leaving room for the prompt,
+// the header, the separator and the help line
+func (p *picker) listHeight(height int) int {
+	available := height - 3
+	if p.options.Header != "" {
+		available--
+	}
+	listHeight := available / 2
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	return listHeight
+}
+
+func (p *picker) preview(index int) string {
+	if p.items[index].Preview == nil {
+		return ""
+	}
+	preview, found := p.previews[index]
+	if !found {
+		preview = p.items[index].Preview()
+		p.previews[index] = preview
+	}
+	return preview
+}
+
+func (p *picker) draw(out io.Writer, width int, height int) {
+	lines := []string{}
+
+	lines = append(lines, fmt.Sprintf("%s %s%s", tty.ColorBoldBlue(">"), string(p.query), tty.ColorBoldBlack(fmt.Sprintf("  %d/%d", len(p.matches), len(p.items)))))
+	if p.options.Header != "" {
+		lines = append(lines, tty.ColorBoldBlack(p.options.Header))
+	}
+
+	listHeight := p.listHeight(height)
+	for row := 0; row < listHeight; row++ {
+		position := p.offset + row
+		if position >= len(p.matches) {
+			lines = append(lines, "")
+			continue
+		}
+
+		index := p.matches[position]
+		marker := " "
+		if p.selected[index] {
+			marker = tty.ColorBoldGreen("+")
+		}
+		line := strings.ReplaceAll(p.items[index].Line, "\n", " ")
+		if position == p.cursor {
+			lines = append(lines, tty.ColorBoldBlue(">")+marker+line)
+		} else {
+			lines = append(lines, " "+marker+line)
+		}
+	}
+
+	lines = append(lines, tty.ColorBoldBlack(strings.Repeat("─", width)))
+
+	previewHeight := height - len(lines) - 1
+	preview := []string{}
+	if len(p.matches) != 0 {
+		preview = strings.Split(p.preview(p.matches[p.cursor]), "\n")
+	}
+	for row := 0; row < previewHeight; row++ {
+		if row < len(preview) {
+			lines = append(lines, preview[row])
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	lines = append(lines, tty.ColorBoldBlack(p.help()))
+
+	var screen strings.Builder
+	screen.WriteString("\x1b[H")
+	for i, line := range lines {
+		screen.WriteString(fit(line, width))
+		screen.WriteString("\x1b[K")
+		if i != len(lines)-1 {
+			screen.WriteString("\r\n")
+		}
+	}
+	io.WriteString(out, screen.String())
+}
+
+func (p *picker) help() string {
+	help := []string{"enter: pick", "esc: cancel"}
+	if p.options.Multi {
+		help = append(help, "tab: select")
+	}
+	for _, binding := range p.options.Bindings {
+		help = append(help, fmt.Sprintf("%s: %s", binding.Key, binding.Action))
+	}
+	return strings.Join(help, "  ")
+}
+
+// fit cuts a line so it takes at most width columns, ignoring color escape sequences
+func fit(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", strings.Repeat(" ", tabWidth))
+
+	var out strings.Builder
+	columns := 0
+	colored := false
+	for len(line) != 0 {
+		if loc := ansiEscapeRegexp.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			out.WriteString(line[:loc[1]])
+			line = line[loc[1]:]
+			colored = true
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+		if r < ' ' {
+			continue
+		}
+		if columns == width {
+			break
+		}
+		out.WriteRune(r)
+		columns++
+	}
+
+	if colored {
+		out.WriteString("\x1b[0m")
+	}
+	return out.String()
+}
+
+// readKey reads the next key pressed, returning the name of the special ones or the rune typed
+func readKey(in *bufio.Reader) (string, rune, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return "", r, err
+	}
+
+	switch {
+	case r == '\r' || r == '\n':
+		return keyEnter, r, nil
+	case r == 0x03 || r == 0x07: // ctrl-c, ctrl-g
+		return keyCancel, r, nil
+	case r == 0x7f || r == 0x08:
+		return keyBackspace, r, nil
+	case r == 0x15: // ctrl-u
+		return keyClear, r, nil
+	case r == 0x17: // ctrl-w
+		return keyWord, r, nil
+	case r == 0x10: // ctrl-p
+		return keyUp, r, nil
+	case r == 0x0e: // ctrl-n
+		return keyDown, r, nil
+	case r == '\t':
+		return keyToggle, r, nil
+	case r == 0x1b:
+		return readEscapeSequence(in)
+	case r < ' ':
+		return fmt.Sprintf("ctrl-%c", 'a'+r-1), r, nil
+	}
+	return "", r, nil
+}
+
+// readEscapeSequence parses the keys sent as escape sequences (arrows, page up/down). A lone escape
+// cancels the picker
+func readEscapeSequence(in *bufio.Reader) (string, rune, error) {
+	if in.Buffered() == 0 {
+		return keyCancel, 0x1b, nil
+	}
+
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return "", r, err
+	}
+	if r != '[' && r != 'O' {
+		return keyIgnore, r, nil
+	}
+
+	sequence := ""
+	for {
+		r, _, err = in.ReadRune()
+		if err != nil {
+			return "", r, err
+		}
+		sequence += string(r)
+		if r >= 0x40 && r <= 0x7e {
+			break
+		}
+	}
+
+	switch sequence {
+	case "A":
+		return keyUp, r, nil
+	case "B":
+		return keyDown, r, nil
+	case "5~":
+		return keyPageUp, r, nil
+	case "6~":
+		return keyPageDown, r, nil
+	}
+	return keyIgnore, r, nil
+}
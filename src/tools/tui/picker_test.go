@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"bufio"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func items(lines ...string) []Item {
+	items := []Item{}
+	for _, line := range lines {
+		line := line
+		items = append(items, Item{Line: line, Preview: func() string { return "preview of " + line }})
+	}
+	return items
+}
+
+func runPicker(t *testing.T, p *picker, keys string) ([]int, string) {
+	size := func() (int, int) { return 80, 24 }
+	picked, action, err := p.run(bufio.NewReader(strings.NewReader(keys)), ioutil.Discard, size)
+	if err != nil {
+		t.Fatalf("picker failed: %v", err)
+	}
+	return picked, action
+}
+
+func TestPickerFiltersFuzzily(t *testing.T) {
+	p := newPicker(items("git log --oneline", "docker run -it", "docker ps -a"), Options{})
+
+	picked, action := runPicker(t, p, "dkrr\r")
+	if !reflect.DeepEqual(picked, []int{1}) || action != ActionPick {
+		t.Errorf("unexpected result: picked %v with action '%s'", picked, action)
+	}
+}
+
+func TestPickerNavigation(t *testing.T) {
+	p := newPicker(items("first", "second", "third"), Options{})
+
+	picked, _ := runPicker(t, p, "\x1b[B\x1b[B\x1b[B\x1b[A\r")
+	if !reflect.DeepEqual(picked, []int{1}) {
+		t.Errorf("expected second item to be picked, got %v", picked)
+	}
+}
+
+func TestPickerBindings(t *testing.T) {
+	p := newPicker(items("first", "second"), Options{Bindings: []Binding{{Key: "ctrl-r", Action: "run"}}})
+
+	picked, action := runPicker(t, p, "\x0e\x12")
+	if !reflect.DeepEqual(picked, []int{1}) || action != "run" {
+		t.Errorf("unexpected result: picked %v with action '%s'", picked, action)
+	}
+}
+
+func TestPickerCancel(t *testing.T) {
+	p := newPicker(items("first", "second"), Options{})
+
+	if picked, _ := runPicker(t, p, "\x03"); len(picked) != 0 {
+		t.Errorf("nothing should be picked when cancelled, got %v", picked)
+	}
+}
+
+func TestPickerMultiSelection(t *testing.T) {
+	p := newPicker(items("first", "second", "third"), Options{Multi: true, Reverse: true})
+
+	picked, _ := runPicker(t, p, "\t\t\r")
+	if !reflect.DeepEqual(picked, []int{1, 2}) {
+		t.Errorf("expected last two items to be picked, got %v", picked)
+	}
+}
+
+func TestFitIgnoresColors(t *testing.T) {
+	line := "\x1b[1;34mlabel\x1b[0m - description"
+	if fitted := fit(line, 8); fitted != "\x1b[1;34mlabel\x1b[0m - \x1b[0m" {
+		t.Errorf("unexpected fitted line: %q", fitted)
+	}
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	enterFullScreen = "\x1b[?1049h\x1b[?25l\x1b[H\x1b[2J"
+	exitFullScreen  = "\x1b[?25h\x1b[?1049l"
+
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// screen is the terminal the picker is drawn on: keys are read from the standard input with echo
+// disabled, and the picker is drawn on the standard error so the standard output is left untouched
+type screen struct {
+	in     io.Reader
+	out    io.Writer
+	reader *terminal.RuneReader
+}
+
+func openTerminal() (*screen, error) {
+	reader := terminal.NewRuneReader(terminal.Stdio{In: os.Stdin, Out: os.Stderr, Err: os.Stderr})
+	if err := reader.SetTermMode(); err != nil {
+		return nil, err
+	}
+
+	io.WriteString(os.Stderr, enterFullScreen)
+
+	return &screen{
+		in:     os.Stdin,
+		out:    os.Stderr,
+		reader: reader,
+	}, nil
+}
+
+func (s *screen) size() (int, int) {
+	width, height, err := terminalSize(os.Stderr.Fd())
+	if err != nil || width <= 0 || height <= 0 {
+		return defaultWidth, defaultHeight
+	}
+	return width, height
+}
+
+func (s *screen) close() {
+	io.WriteString(s.out, exitFullScreen)
+	s.reader.RestoreTermMode()
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package tui
+
+import "golang.org/x/sys/unix"
+
+func terminalSize(fd uintptr) (int, int, error) {
+	size, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(size.Col), int(size.Row), nil
+}
@@ -0,0 +1,11 @@
+package tui
+
+import "golang.org/x/sys/windows"
+
+func terminalSize(fd uintptr) (int, int, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, 0, err
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
@@ -1,8 +1,11 @@
 package tools
 
 import (
+	"fmt"
 	"log"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
@@ -22,3 +25,26 @@ func StringToUUID(str string) uuid.UUID {
 	}
 	return id
 }
+
+// ParseDuration extends time.ParseDuration with days (30d) and weeks (2w)
+func ParseDuration(str string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if strings.HasSuffix(str, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(str, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration '%s'", str)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s'", str)
+	}
+	return duration, nil
+}
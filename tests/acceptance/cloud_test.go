@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/models"
 	"github.com/dplabs/cbox/src/tools/tty"
 	"github.com/dplabs/cbox/tests"
 )
@@ -123,3 +124,86 @@ func TestCopyingCloudCommands(t *testing.T) {
 	ctrl.CloudSpaceUnpublish("@test:default")
 	tests.AssertOutputContains(t, "Space unpublished successfully!", "failed to unpublish space")
 }
+
+func TestSyncingWithCloud(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{testUserJWTToken}
+	ctrl.CloudLogin()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "URL", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedOutput = ""
+	ctrl.CloudSpaceSync("@default", nil)
+	tests.AssertOutputContains(t, "Space synced successfully! (sync #1: 1 pushed, 0 pulled, 0 conflicts)", "failed to sync space for the first time")
+
+	tty.MockedInput = []string{"other-command", "This is another test command", "URL", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedOutput = ""
+	ctrl.CloudSpaceSync("@default", nil)
+	tests.AssertOutputContains(t, "Space synced successfully! (sync #2: 1 pushed, 0 pulled, 0 conflicts)", "failed to push changes")
+
+	tty.MockedOutput = ""
+	ctrl.CloudSpaceSync("@default", nil)
+	tests.AssertOutputContains(t, "Nothing to sync", "space not in sync after syncing it")
+
+	tty.MockedOutput = ""
+	ctrl.CloudSpaceUnpublish("@test:default")
+	tests.AssertOutputContains(t, "Space unpublished successfully!", "failed to unpublish space")
+}
+
+func TestMergingCloudChanges(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { controllers.CommandFieldsOption = map[string]string{} }()
+
+	tty.MockedInput = []string{testUserJWTToken}
+	ctrl.CloudLogin()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "URL", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	ctrl.CloudSpaceSync("@default", nil)
+
+	controllers.CommandFieldsOption = map[string]string{models.SearchFieldCode: "CODE --local"}
+	ctrl.CommandEdit("test-command")
+
+	tty.MockedOutput = ""
+	defaultSpace := "@default"
+	ctrl.CloudCopy("@test:default", &defaultSpace)
+	tests.AssertOutputContains(t, "Commands copied successfully into '@default'!", "failed to merge cloud commands into a changed one")
+
+	tty.MockedOutput = ""
+	controllers.SourceOnlyFlag = true
+	defer func() { controllers.SourceOnlyFlag = false }()
+	ctrl.CommandView("test-command")
+	tests.AssertOutputContains(t, "CODE --local", "local change lost merging the cloud version")
+
+	ctrl.CloudSpaceUnpublish("@test:default")
+}
+
+func TestDiffingWithCloud(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{testUserJWTToken}
+	ctrl.CloudLogin()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "URL", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedOutput = ""
+	ctrl.CloudSpaceDiff("@default", nil)
+	tests.AssertOutputContains(t, "1 added, 0 changed, 0 removed, 0 unchanged", "unpublished space should only add commands")
+
+	ctrl.CloudSpaceSync("@default", nil)
+
+	tty.MockedOutput = ""
+	ctrl.CloudSpaceDiff("@default", nil)
+	tests.AssertOutputContains(t, "No differences", "space just synced should not differ")
+
+	ctrl.CloudSpaceUnpublish("@test:default")
+}
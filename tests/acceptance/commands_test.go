@@ -2,10 +2,12 @@ package acceptance_tests
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/models"
 	"github.com/dplabs/cbox/src/tools/tty"
 	"github.com/dplabs/cbox/tests"
 )
@@ -181,3 +183,188 @@ func TestCopyCommandClashing(t *testing.T) {
 	// ctrl.CommandCopy("test-command@default", &targetSpace)
 	// tests.AssertOutputContains(t, "Command copied successfully!", "could not copy command to @test-space")
 }
+
+func TestRunCommand(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	controllers.ShellOption = "/bin/sh"
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "exit 3", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	exitCode := ctrl.CommandRun("test-command@default")
+	if exitCode != 3 {
+		t.Errorf("exit code of the command was not returned: expected = 3, got = %d", exitCode)
+	}
+
+	tty.MockedInput = []string{"test-command-ok", "This is a test command", "url", "true", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	exitCode = ctrl.CommandRun("test-command-ok@default")
+	if exitCode != 0 {
+		t.Errorf("successful command returned a non-zero exit code: %d", exitCode)
+	}
+}
+
+func TestViewCommandWithVariables(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "echo {{greeting:hello}} {{name}}", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "Variables: greeting (default: hello), name", "could not display the variables of the command")
+
+	controllers.SourceOnlyFlag = true
+	defer func() { controllers.SourceOnlyFlag = false }()
+
+	tty.MockedOutput = ""
+	tty.MockedInput = []string{"world"}
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "echo hello world", "could not resolve the variables of the command")
+
+	controllers.VariablesOption = []string{"greeting=bye", "name=moon"}
+	defer func() { controllers.VariablesOption = nil }()
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "echo bye moon", "could not resolve the variables of the command using --var")
+}
+
+func TestCommandHistoryAndRevert(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "CODE-edited"}
+	ctrl.CommandEdit("test-command@default")
+
+	tty.MockedOutput = ""
+	ctrl.CommandHistory("test-command@default")
+	tests.AssertOutputContains(t, "Revision 1", "first revision not displayed")
+	tests.AssertOutputContains(t, "Revision 2 (current)", "current revision not displayed")
+	tests.AssertOutputContains(t, "- CODE", "removed code not displayed in the diff")
+	tests.AssertOutputContains(t, "+ CODE-edited", "added code not displayed in the diff")
+
+	tty.MockedOutput = ""
+	ctrl.CommandRevert("test-command@default", "1")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "\nCODE\n", "command not reverted to its first revision")
+
+	tty.MockedOutput = ""
+	ctrl.CommandHistory("test-command@default")
+	tests.AssertOutputContains(t, "Revision 3 (current)", "reverting a command should record a new revision")
+}
+
+func TestAddAndEditCommandWithFlags(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() {
+		controllers.CommandFieldsOption = map[string]string{}
+		controllers.AddTagsOption = []string{}
+		controllers.RemoveTagsOption = []string{}
+	}()
+
+	controllers.CommandFieldsOption = map[string]string{
+		models.SearchFieldLabel:       "pods",
+		models.SearchFieldDescription: "List pods",
+		models.SearchFieldCode:        "-",
+	}
+	controllers.AddTagsOption = []string{"k8s", "old"}
+	tty.MockedInput = []string{"kubectl get pods\n"}
+	ctrl.CommandAdd(nil)
+	tests.AssertOutputContains(t, "Command stored successfully!", "could not add command with flags")
+
+	controllers.CommandFieldsOption = map[string]string{models.SearchFieldDescription: "List every pod"}
+	controllers.AddTagsOption = []string{"kubectl"}
+	controllers.RemoveTagsOption = []string{"old"}
+	tty.MockedOutput = ""
+	ctrl.CommandEdit("pods")
+	tests.AssertOutputContains(t, "Command updated successfully!", "could not edit command with flags")
+
+	controllers.CommandFieldsOption = map[string]string{}
+	controllers.AddTagsOption = []string{}
+	controllers.RemoveTagsOption = []string{}
+	controllers.SourceOnlyFlag = true
+	defer func() { controllers.SourceOnlyFlag = false }()
+	tty.MockedOutput = ""
+	ctrl.CommandView("pods")
+	tests.AssertOutputContains(t, "kubectl get pods", "code read from standard input not stored")
+
+	controllers.SourceOnlyFlag = false
+	tty.MockedOutput = ""
+	ctrl.CommandView("pods")
+	tests.AssertOutputContains(t, "Description: List every pod", "description not changed with flags")
+	tests.AssertOutputContains(t, "Tags: k8s, kubectl", "tags not changed with flags")
+}
+
+func TestEditCommandInEditor(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { controllers.EditorFlag = false }()
+
+	// the first edition is invalid, so the editor has to be opened again showing the error
+	marks := tests.FakeEditor(t, `
+if [ ! -f "$(dirname $0)/opened" ]; then
+	touch "$(dirname $0)/opened"
+	sed -i 's/^label: .*/label: Not Valid/' "$1"
+else
+	grep -q '^# ERROR: invalid characters in label' "$1" && touch "$(dirname $0)/error-shown"
+	sed -i -e 's/^label: .*/label: pods-all/' -e 's/^kubectl get pods$/kubectl get pods -A\nkubectl top pods/' "$1"
+fi
+`)
+
+	tty.MockedInput = []string{"pods", "List pods", "url", "kubectl get pods", "k8s"}
+	ctrl.CommandAdd(nil)
+
+	controllers.EditorFlag = true
+	tty.MockedOutput = ""
+	ctrl.CommandEdit("pods")
+	tests.AssertOutputContains(t, "Command updated successfully!", "could not edit command in editor")
+
+	if _, err := os.Stat(filepath.Join(marks, "error-shown")); err != nil {
+		t.Errorf("editor not reopened with the validation error")
+	}
+
+	controllers.SourceOnlyFlag = true
+	defer func() { controllers.SourceOnlyFlag = false }()
+	tty.MockedOutput = ""
+	ctrl.CommandView("pods-all")
+	tests.AssertOutputContains(t, "kubectl get pods -A\nkubectl top pods", "multiline code not edited in editor")
+}
+
+func TestCopyCodeToClipboard(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() {
+		controllers.ClipboardFlag = false
+		controllers.VariablesOption = nil
+	}()
+
+	tty.MockedInput = []string{"greet", "Greet someone", "url", "echo hello {{name}}", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	controllers.VariablesOption = []string{"name=world"}
+	tty.MockedClipboard = ""
+	tty.MockedOutput = ""
+	ctrl.CommandCopyCode("greet@default")
+	tests.AssertOutputContains(t, "Code of 'greet@default' copied to the clipboard", "could not copy code")
+	if tty.MockedClipboard != "echo hello world" {
+		t.Errorf("code with variables resolved not copied to the clipboard: %q", tty.MockedClipboard)
+	}
+
+	controllers.ClipboardFlag = true
+	controllers.VariablesOption = []string{"name=moon"}
+	tty.MockedClipboard = ""
+	ctrl.CommandView("greet@default")
+	if tty.MockedClipboard != "echo hello moon" {
+		t.Errorf("code not copied to the clipboard when viewing the command: %q", tty.MockedClipboard)
+	}
+}
@@ -0,0 +1,68 @@
+package acceptance_tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/tests"
+)
+
+func TestFormatTemplate(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { console.FormatTemplate = "" }()
+
+	tty.MockedInput = []string{"test-command", "This is a long description", "url", "code", "tag-1 tag-2"}
+	ctrl.CommandAdd(nil)
+
+	console.FormatTemplate = `{{.Label}}\t{{.Selector}}\t{{join .Tags ","}}\t{{.Description | truncate 9}}`
+
+	tty.MockedOutput = ""
+	ctrl.CommandList(nil)
+	if tty.MockedOutput != "test-command\ttest-command@default\ttag-1,tag-2\tThis is a\n" {
+		t.Errorf("unexpected formatted listing: %q", tty.MockedOutput)
+	}
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	if tty.MockedOutput != "test-command\ttest-command@default\ttag-1,tag-2\tThis is a\n" {
+		t.Errorf("unexpected formatted command: %q", tty.MockedOutput)
+	}
+
+	console.FormatTemplate = `{{.Label}}: {{len .Entries}}`
+
+	tty.MockedOutput = ""
+	ctrl.SpacesList()
+	if tty.MockedOutput != "default: 1\n" {
+		t.Errorf("unexpected formatted spaces: %q", tty.MockedOutput)
+	}
+
+	console.FormatTemplate = `#{{.}}`
+
+	tty.MockedOutput = ""
+	ctrl.TagsList(nil)
+	if tty.MockedOutput != "#tag-1\n#tag-2\n" {
+		t.Errorf("unexpected formatted tags: %q", tty.MockedOutput)
+	}
+}
+
+func TestDefaultFormatTemplate(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { delete(console.DefaultTemplates, console.TemplateCommands) }()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "code", "tag"}
+	ctrl.CommandAdd(nil)
+
+	console.DefaultTemplates[console.TemplateCommands] = `{{.Selector}} | {{color "red" .Label}}`
+
+	tty.MockedOutput = ""
+	ctrl.CommandList(nil)
+	tests.AssertOutputContains(t, "test-command@default | ", "default template not used for listings")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "This is a test command", "default listing template should not be used to view a command")
+}
@@ -0,0 +1,83 @@
+package acceptance_tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/tests"
+)
+
+func TestImportHistory(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	historyFile := path.Join(dir, "zsh_history")
+	content := ": 1600000000:0;ls -la\n: 1600000010:0;git log --oneline\n: 1600000020:0;ls -la\n: 1600000030:0;Docker PS -a\n"
+	if err := ioutil.WriteFile(historyFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	controllers.HistoryShellOption = "zsh"
+	controllers.HistoryFileOption = historyFile
+	defer func() {
+		controllers.HistoryShellOption = ""
+		controllers.HistoryFileOption = ""
+		controllers.GrepOption = ""
+	}()
+
+	controllers.GrepOption = "^(ls|git)"
+	tty.MockedOutput = ""
+	ctrl.ImportHistory(nil)
+	tests.AssertOutputContains(t, "2 commands imported successfully", "could not import commands from history")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("ls-la@default")
+	tests.AssertOutputContains(t, "Imported from zsh history", "imported command not found")
+
+	controllers.GrepOption = ""
+	tty.MockedOutput = ""
+	ctrl.ImportHistory(nil)
+	tests.AssertOutputContains(t, "1 commands imported successfully", "already imported commands should be skipped")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("docker-ps-a@default")
+	tests.AssertOutputContains(t, "Docker PS -a", "imported command with an invalid label")
+}
+
+func TestImportExportCheatsheets(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	space := "@cheats"
+
+	tty.MockedOutput = ""
+	ctrl.ImportCheatsheets("navi", "../../src/tools/cheatsheets/testdata", &space)
+	tests.AssertOutputContains(t, "5 commands imported successfully into space '@cheats'", "could not import navi cheats")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("change-branch@cheats")
+	tests.AssertOutputContains(t, "git checkout {{branch:main}}", "navi variables not imported")
+
+	tty.MockedOutput = ""
+	ctrl.ImportCheatsheets("navi", "../../src/tools/cheatsheets/testdata/git.cheat", &space)
+	tests.AssertOutputContains(t, "0 commands imported successfully", "already imported commands should be skipped")
+
+	exported := path.Join(dir, "snippet.toml")
+	controllers.ExportFileOption = exported
+	defer func() { controllers.ExportFileOption = "" }()
+
+	ctrl.ExportCheatsheet("pet", space)
+
+	content, err := ioutil.ReadFile(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `command = "git checkout <branch=main>"`) {
+		t.Errorf("space not exported as pet snippets: %s", content)
+	}
+}
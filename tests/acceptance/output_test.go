@@ -0,0 +1,64 @@
+package acceptance_tests
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/tests"
+)
+
+func TestMachineReadableOutput(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { console.OutputFormat = "" }()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "CODE\twith tab", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	console.OutputFormat = console.OutputJSON
+
+	tty.MockedOutput = ""
+	ctrl.CommandList(nil)
+	var commands []map[string]interface{}
+	if err := json.Unmarshal([]byte(tty.MockedOutput), &commands); err != nil {
+		t.Fatalf("listing is not valid JSON: %v\n%s", err, tty.MockedOutput)
+	}
+	if len(commands) != 1 || commands[0]["id"] != "test-command@default" || commands[0]["code"] != "CODE\twith tab" {
+		t.Errorf("unexpected JSON listing: %s", tty.MockedOutput)
+	}
+
+	tty.MockedOutput = ""
+	ctrl.SpacesList()
+	var spaces []map[string]interface{}
+	if err := json.Unmarshal([]byte(tty.MockedOutput), &spaces); err != nil || len(spaces) != 1 || spaces[0]["label"] != "default" {
+		t.Errorf("unexpected JSON spaces listing (%v): %s", err, tty.MockedOutput)
+	}
+
+	console.OutputFormat = console.OutputYAML
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "label: test-command\n", "command not displayed as YAML")
+	if strings.Contains(tty.MockedOutput, "- - -") {
+		t.Errorf("machine readable output should not contain decorations: %s", tty.MockedOutput)
+	}
+
+	console.OutputFormat = console.OutputTSV
+
+	tty.MockedOutput = ""
+	ctrl.CommandList(nil)
+	row := strings.Split(strings.TrimSuffix(tty.MockedOutput, "\n"), "\t")
+	if len(row) != 8 || row[0] != "test-command@default" || row[3] != "test-tag" || row[5] != `CODE\twith tab` {
+		t.Errorf("unexpected TSV row: %q", tty.MockedOutput)
+	}
+
+	tty.MockedOutput = ""
+	ctrl.TagsList(nil)
+	if tty.MockedOutput != "test-tag\n" {
+		t.Errorf("unexpected TSV tags: %q", tty.MockedOutput)
+	}
+}
@@ -0,0 +1,140 @@
+package acceptance_tests
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/core"
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/tools/console"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/tests"
+)
+
+func TestSearchIsRankedByRelevance(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { controllers.ListingsSortOption = "name" }()
+
+	tty.MockedInput = []string{"apply-manifests", "Apply manifests", "url", "kubectl apply -f .", "k8s"}
+	ctrl.CommandAdd(nil)
+	tty.MockedInput = []string{"deploy", "Deploy the application", "url", "helm upgrade app", "k8s"}
+	ctrl.CommandAdd(nil)
+	tty.MockedInput = []string{"containers", "Check containers before trying to deploy", "url", "docker ps", "docker"}
+	ctrl.CommandAdd(nil)
+
+	controllers.ListingsModeOption = "static"
+	controllers.ListingsSortOption = console.SortRelevance
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, "deplyo")
+	deploy := strings.Index(tty.MockedOutput, "deploy@default")
+	containers := strings.Index(tty.MockedOutput, "containers@default")
+	if deploy == -1 || containers == -1 || deploy > containers {
+		t.Errorf("label match should be listed before description match: %s", tty.MockedOutput)
+	}
+	if strings.Contains(tty.MockedOutput, "apply-manifests@default") {
+		t.Errorf("search returned non matching commands: %s", tty.MockedOutput)
+	}
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, `tag:k8s -label:deploy`)
+	tests.AssertOutputContains(t, "apply-manifests@default", "could not search by tag")
+	if strings.Contains(tty.MockedOutput, "deploy@default") {
+		t.Errorf("search returned excluded commands: %s", tty.MockedOutput)
+	}
+}
+
+func TestSearchWithRegex(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() {
+		controllers.RegexFlag = false
+		controllers.SearchFieldsOption = []string{}
+		tty.DisableColors = true
+		console.HighlightMatches(nil)
+	}()
+
+	tty.MockedInput = []string{"tunnel-db", "Forward the remote DB port", "url", "ssh bastion -L 5432:db:5432", "ssh"}
+	ctrl.CommandAdd(nil)
+	tty.MockedInput = []string{"connect", "Connect to bastion", "url", "ssh bastion", "ssh"}
+	ctrl.CommandAdd(nil)
+
+	controllers.ListingsModeOption = "static"
+	controllers.RegexFlag = true
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, `ssh .* -L [0-9]+`)
+	tests.AssertOutputContains(t, "tunnel-db@default", "could not search commands with a regular expression")
+	if strings.Contains(tty.MockedOutput, "connect@default") {
+		t.Errorf("regex search returned non matching commands: %s", tty.MockedOutput)
+	}
+
+	controllers.SearchFieldsOption = []string{"label"}
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, `^c`)
+	tests.AssertOutputContains(t, "connect@default", "could not search labels with a regular expression")
+	if strings.Contains(tty.MockedOutput, "tunnel-db@default") {
+		t.Errorf("regex search in labels returned non matching commands: %s", tty.MockedOutput)
+	}
+
+	tty.DisableColors = false
+	controllers.SearchFieldsOption = []string{"description"}
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, `remote`)
+	tests.AssertOutputContains(t, "Forward the "+tty.ColorBgYellow("remote")+" DB port", "matches not highlighted")
+}
+
+func TestSearchAcrossSpaces(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() {
+		controllers.SearchSpacesOption = []string{}
+		controllers.RemoteFlag = false
+	}()
+
+	tty.MockedInput = []string{"docker-ps", "List containers", "url", "docker ps", "docker"}
+	ctrl.CommandAdd(nil)
+	tty.MockedInput = []string{"work", "Work commands"}
+	ctrl.SpacesCreate()
+	work := "@work"
+	tty.MockedInput = []string{"docker-logs", "Show container logs", "url", "docker logs -f", "docker"}
+	ctrl.CommandAdd(&work)
+	tty.MockedInput = []string{"docker-prune", "Remove unused containers", "url", "docker system prune", "docker"}
+	ctrl.CommandAdd(&work)
+
+	controllers.ListingsModeOption = "static"
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, "docker")
+	tests.AssertOutputContains(t, "default (1 command)", "could not group results of the default space")
+	tests.AssertOutputContains(t, "work (2 commands)", "could not group results of the work space")
+	tests.AssertOutputContains(t, "3 commands found in 2 spaces", "could not count results")
+
+	controllers.SearchSpacesOption = []string{"work"}
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, "docker")
+	tests.AssertOutputContains(t, "docker-logs@work", "could not search a given space")
+	if strings.Contains(tty.MockedOutput, "docker-ps@default") {
+		t.Errorf("search returned commands from spaces not given: %s", tty.MockedOutput)
+	}
+
+	remote := &models.Selector{Space: "tools", NamespaceType: models.TypeOrganization, Namespace: "acme"}
+	build := &models.Command{
+		Meta:  models.Meta{ID: remote.CloneForItem("docker-build").String(), Selector: remote.CloneForItem("docker-build")},
+		Label: "docker-build", Description: "Build the image", Code: "docker build .", Tags: []string{"docker"},
+	}
+	err := core.CloudCacheStore(&models.Space{Meta: models.Meta{ID: remote.String(), Selector: remote}, Label: "tools", Entries: []*models.Command{build}})
+	if err != nil {
+		t.Fatalf("could not cache cloud space: %v", err)
+	}
+
+	controllers.SearchSpacesOption = []string{}
+	controllers.RemoteFlag = true
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, "docker")
+	tests.AssertOutputContains(t, "acme/tools (1 command, remote)", "could not search cached cloud spaces")
+	tests.AssertOutputContains(t, "[remote] docker-build", "remote commands are not marked")
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/dplabs/cbox/src/controllers"
 	"github.com/dplabs/cbox/src/tools/tty"
 	"github.com/dplabs/cbox/tests"
 )
@@ -66,3 +67,19 @@ func TestCreateEditDestroySpaces(t *testing.T) {
 		t.Errorf("space deletion left some spaces behind: %s", tty.MockedOutput)
 	}
 }
+
+func TestEditSpaceInEditor(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { controllers.EditorFlag = false }()
+
+	tests.FakeEditor(t, `printf 'label: work\ndescription: Work commands\n' > "$1"`)
+
+	tty.MockedInput = []string{"test-space", "This is a test space"}
+	ctrl.SpacesCreate()
+
+	controllers.EditorFlag = true
+	tty.MockedOutput = ""
+	ctrl.SpacesEdit("@test-space")
+	tests.AssertOutputContains(t, "@work - Work commands", "space edition in editor failed")
+}
@@ -0,0 +1,149 @@
+package acceptance_tests
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/repository"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/tests"
+	"github.com/spf13/viper"
+)
+
+func TestMigrateStorageToSQLite(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer viper.Set("cbox.storage.backend", repository.StorageBackendJSON)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "kubectl apply -f deployment.yml", "test-tag k8s"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedInput = []string{"other-command", "Another command", "url", "ls -la", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	controllers.StorageBackendOption = repository.StorageBackendSQLite
+	tty.MockedOutput = ""
+	ctrl.StorageMigrate()
+	tests.AssertOutputContains(t, "1 spaces migrated successfully!", "could not migrate storage to sqlite")
+
+	if _, err := os.Stat(dir + "/.cbox/cbox.db"); err != nil {
+		t.Fatalf("sqlite database not created: %v", err)
+	}
+
+	controllers.ListingsModeOption = "static"
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, "APPLY")
+	tests.AssertOutputContains(t, "test-command@default", "could not search commands using sqlite storage")
+	if strings.Contains(tty.MockedOutput, "other-command@default") {
+		t.Errorf("search using sqlite storage returned non matching commands: %s", tty.MockedOutput)
+	}
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, "ls")
+	tests.AssertOutputContains(t, "other-command@default", "could not search commands with short criteria using sqlite storage")
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, `code:"kubectl apply" OR tag:missing`)
+	tests.AssertOutputContains(t, "test-command@default", "could not search commands by phrase using sqlite storage")
+
+	tty.MockedOutput = ""
+	ctrl.SearchCommands(nil, `tag:test-tag -tag:k8s`)
+	tests.AssertOutputContains(t, "other-command@default", "could not search commands by tag using sqlite storage")
+	if strings.Contains(tty.MockedOutput, "test-command@default") {
+		t.Errorf("search using sqlite storage returned excluded commands: %s", tty.MockedOutput)
+	}
+
+	tty.MockedOutput = ""
+	ctrl.TagsList(nil)
+	tests.AssertOutputContains(t, "* k8s", "could not list tags using sqlite storage")
+	tests.AssertOutputContains(t, "* test-tag", "could not list tags using sqlite storage")
+
+	tty.MockedOutput = ""
+	ctrl = controllers.InitController(dir)
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "kubectl apply -f deployment.yml", "could not load commands from sqlite storage")
+}
+
+func TestMigrateStorageToJSON(t *testing.T) {
+	viper.Set("cbox.storage.backend", repository.StorageBackendSQLite)
+	defer viper.Set("cbox.storage.backend", repository.StorageBackendJSON)
+
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "kubectl apply -f deployment.yml", "test-tag k8s"}
+	ctrl.CommandAdd(nil)
+
+	if _, err := os.Stat(dir + "/.cbox/spaces"); !os.IsNotExist(err) {
+		t.Fatalf("spaces directory created using sqlite storage: %v", err)
+	}
+
+	controllers.StorageBackendOption = repository.StorageBackendJSON
+	tty.MockedOutput = ""
+	ctrl.StorageMigrate()
+	tests.AssertOutputContains(t, "1 spaces migrated successfully!", "could not migrate storage to json")
+
+	if _, err := os.Stat(dir + "/.cbox/spaces/default.json"); err != nil {
+		t.Fatalf("space file not created: %v", err)
+	}
+
+	tty.MockedOutput = ""
+	ctrl = controllers.InitController(dir)
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "kubectl apply -f deployment.yml", "could not load commands from json storage")
+}
+
+func TestMigrateStorageDoesNotRestoreDeletedSpaces(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer viper.Set("cbox.storage.backend", repository.StorageBackendJSON)
+
+	tty.MockedInput = []string{"test-space", "This is a test space"}
+	ctrl.SpacesCreate()
+
+	controllers.StorageBackendOption = repository.StorageBackendSQLite
+	ctrl.StorageMigrate()
+
+	controllers.StorageBackendOption = repository.StorageBackendJSON
+	ctrl.StorageMigrate()
+
+	ctrl = controllers.InitController(dir)
+	ctrl.SpacesDestroy("@test-space")
+
+	controllers.StorageBackendOption = repository.StorageBackendSQLite
+	tty.MockedOutput = ""
+	ctrl.StorageMigrate()
+	tests.AssertOutputContains(t, "1 spaces migrated successfully!", "could not migrate storage to sqlite again")
+
+	tty.MockedOutput = ""
+	ctrl = controllers.InitController(dir)
+	ctrl.SpacesList()
+	if strings.Contains(tty.MockedOutput, "@test-space") {
+		t.Errorf("space deleted between migrations restored by the second one: %s", tty.MockedOutput)
+	}
+	tests.AssertOutputContains(t, "@default", "space kept between migrations not found")
+}
+
+func TestStoresPersistSettings(t *testing.T) {
+	defer viper.Set("cbox.storage.backend", repository.StorageBackendJSON)
+
+	for _, backend := range repository.StorageBackends() {
+		viper.Set("cbox.storage.backend", backend)
+
+		ctrl, dir := tests.InitController()
+		defer os.RemoveAll(dir)
+
+		ctrl.ConfigSet("cbox.results.sort", "date")
+		ctrl.ConfigSave()
+
+		viper.Set("cbox.results.sort", nil)
+		ctrl = controllers.InitController(dir)
+
+		tty.MockedOutput = ""
+		ctrl.ConfigGet("cbox.results.sort")
+		tests.AssertOutputContains(t, "date", "settings not persisted by the "+backend+" store")
+	}
+}
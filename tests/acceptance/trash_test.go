@@ -0,0 +1,120 @@
+package acceptance_tests
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/controllers"
+	"github.com/dplabs/cbox/src/tools/tty"
+	"github.com/dplabs/cbox/tests"
+)
+
+func TestDeleteAndRestoreCommand(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	tty.MockedOutput = ""
+	ctrl.CommandDelete("test-command@default")
+
+	tty.MockedOutput = ""
+	ctrl.TrashList()
+	tests.AssertOutputContains(t, "test-command@default - command", "deleted command not found in trash")
+
+	tty.MockedOutput = ""
+	ctrl.TrashRestore("test-command@default")
+	tests.AssertOutputContains(t, "Restored successfully", "could not restore command")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "CODE", "restored command not found in its space")
+
+	tty.MockedOutput = ""
+	ctrl.TrashList()
+	tests.AssertOutputContains(t, "Trash is empty", "restored command still in trash")
+}
+
+func TestRestoreCommandOverwritingMovesItToTrash(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+	defer func() { controllers.ForceFlag = false }()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "OLD CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+	ctrl.CommandDelete("test-command@default")
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "NEW CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+
+	controllers.ForceFlag = true
+	tty.MockedOutput = ""
+	ctrl.TrashRestore("test-command@default")
+	tests.AssertOutputContains(t, "Restored successfully", "could not restore command")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "OLD CODE", "restored command not found in its space")
+
+	tty.MockedOutput = ""
+	ctrl.TrashList()
+	tests.AssertOutputContains(t, "test-command@default - command", "command overwritten by restore not moved to trash")
+
+	tty.MockedOutput = ""
+	ctrl.TrashRestore("test-command@default")
+	ctrl.CommandView("test-command@default")
+	tests.AssertOutputContains(t, "NEW CODE", "command overwritten by restore could not be restored")
+}
+
+func TestDestroyAndRestoreSpace(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{"test-space", "This is a test space"}
+	ctrl.SpacesCreate()
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "CODE", "test-tag"}
+	space := "@test-space"
+	ctrl.CommandAdd(&space)
+
+	ctrl.SpacesDestroy("@test-space")
+
+	tty.MockedOutput = ""
+	ctrl.TrashList()
+	tests.AssertOutputContains(t, "@test-space - space with 1 commands", "destroyed space not found in trash")
+
+	tty.MockedOutput = ""
+	ctrl.TrashRestore("@test-space")
+	tests.AssertOutputContains(t, "Restored successfully", "could not restore space")
+
+	tty.MockedOutput = ""
+	ctrl.CommandView("test-command@test-space")
+	tests.AssertOutputContains(t, "CODE", "commands of the restored space not found")
+}
+
+func TestEmptyTrash(t *testing.T) {
+	ctrl, dir := tests.InitController()
+	defer os.RemoveAll(dir)
+
+	tty.MockedInput = []string{"test-command", "This is a test command", "url", "CODE", "test-tag"}
+	ctrl.CommandAdd(nil)
+	ctrl.CommandDelete("test-command@default")
+
+	controllers.OlderThanOption = "30d"
+	ctrl.TrashEmpty()
+
+	tty.MockedOutput = ""
+	ctrl.TrashList()
+	if !strings.Contains(tty.MockedOutput, "test-command@default") {
+		t.Errorf("recently deleted command should not be removed from trash: %s", tty.MockedOutput)
+	}
+
+	controllers.OlderThanOption = ""
+	ctrl.TrashEmpty()
+
+	tty.MockedOutput = ""
+	ctrl.TrashList()
+	tests.AssertOutputContains(t, "Trash is empty", "trash not emptied")
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dplabs/cbox/src/core"
 	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/repository"
 	"github.com/dplabs/cbox/tests"
 )
 
@@ -19,15 +20,27 @@ const (
 
 var cloud *models.Cloud
 
+// TestMain runs the whole suite once for every storage backend available
 func TestMain(m *testing.M) {
-	cbox := tests.InitializeCBox()
-	cloud = cloudConnect(cbox, testUserJWTToken)
+	exitCode := 0
 
-	if !strings.Contains(cloud.URL, "test") {
-		panic("test setup: cloud test environment not set properly")
+	for _, backend := range repository.StorageBackends() {
+		os.Setenv("CBOX_STORAGE_BACKEND", backend)
+		log.Printf("running integration tests using storage backend '%s'", backend)
+
+		cbox := tests.InitializeCBox()
+		cloud = cloudConnect(cbox, testUserJWTToken)
+
+		if !strings.Contains(cloud.URL, "test") {
+			panic("test setup: cloud test environment not set properly")
+		}
+
+		if code := m.Run(); code != 0 {
+			exitCode = code
+		}
 	}
 
-	os.Exit(m.Run())
+	os.Exit(exitCode)
 }
 
 func cloudConnect(cbox *models.CBox, jwt string) *models.Cloud {
@@ -74,12 +74,13 @@ func TestCommandEdition(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	previous := *c
 	previousLabel := c.Label
 	newLabel := tests.RandString(8)
 
 	c.Label = newLabel
 
-	space.CommandEdit(c, previousLabel)
+	space.CommandEdit(c, previous, "")
 
 	cboxInstance = tests.ReloadCBox(cboxInstance)
 
@@ -123,10 +124,10 @@ func TestCommandLabelUniquenessOnEdition(t *testing.T) {
 	c1 := createCommand(t, space)
 	c2 := createCommand(t, space)
 
-	previousLabel := c2.Label
+	previous := *c2
 	c2.Label = c1.Label
 
-	err := space.CommandEdit(c2, previousLabel)
+	err := space.CommandEdit(c2, previous, "")
 	if err == nil {
 		t.Fatalf("labels have to be unique within an space after editing a command")
 	}
@@ -150,3 +151,34 @@ func TestCommandTagging(t *testing.T) {
 		t.Errorf("Removed tag still found")
 	}
 }
+
+func TestCommandRevisionsArePersisted(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	c := createCommand(t, space)
+
+	previous := *c
+	c.Code = tests.RandString(30)
+	if err := space.CommandEdit(c, previous, "someone"); err != nil {
+		t.Fatal(err)
+	}
+
+	cboxInstance = tests.ReloadCBox(cboxInstance)
+
+	s, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := s.CommandFind(c.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored.Revisions) != 2 {
+		t.Fatalf("expected 2 revisions stored, got %d", len(stored.Revisions))
+	}
+	if stored.Revisions[0].Code != previous.Code || stored.Revisions[1].Code != c.Code || stored.Revisions[1].Author != "someone" {
+		t.Errorf("revisions not stored properly: %+v", stored.Revisions)
+	}
+}
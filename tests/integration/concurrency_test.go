@@ -0,0 +1,300 @@
+package integration_tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dplabs/cbox/src/models"
+	"github.com/dplabs/cbox/src/repository"
+	"github.com/dplabs/cbox/tests"
+)
+
+// loadConcurrently simulates several cbox processes loading the same repository at the same time
+// and then storing their own changes
+func loadConcurrently(t *testing.T, writers int, change func(i int, space *models.Space)) {
+	repos := make([]*repository.Repository, writers)
+	for i := range repos {
+		repos[i] = repository.InitRepository("/tmp")
+	}
+
+	var loaded sync.WaitGroup
+	loaded.Add(writers)
+
+	var done sync.WaitGroup
+	done.Add(writers)
+
+	for i, repo := range repos {
+		go func(i int, repo *repository.Repository) {
+			defer done.Done()
+
+			spaces, _ := repo.LoadSpaces()
+
+			loaded.Done()
+			loaded.Wait()
+
+			for _, space := range spaces {
+				if space.Label != "default" {
+					change(i, space)
+				}
+			}
+			repo.Save(spaces)
+		}(i, repo)
+	}
+
+	done.Wait()
+}
+
+func TestConcurrentWritersDoNotLoseCommands(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	cboxInstance = tests.ReloadCBox(cboxInstance)
+
+	const writers = 8
+
+	labels := make([]string, writers)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("writer-%d-%s", i, tests.RandString(4))
+	}
+
+	loadConcurrently(t, writers, func(i int, s *models.Space) {
+		command := models.Command{
+			Label: labels[i],
+			Code:  tests.RandString(30),
+		}
+		command.Selector = s.Selector.CloneForItem(command.Label)
+		s.CommandAdd(&command, false)
+	})
+
+	cboxInstance = tests.ReloadCBox(nil)
+
+	s, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, label := range labels {
+		if _, err := s.CommandFind(label); err != nil {
+			t.Errorf("command stored by a concurrent writer was lost: %v", err)
+		}
+	}
+}
+
+func TestConcurrentWritersMergeDeletions(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	deleted := createCommand(t, space)
+	kept := createCommand(t, space)
+	cboxInstance = tests.ReloadCBox(cboxInstance)
+
+	added := tests.RandString(8)
+
+	loadConcurrently(t, 2, func(i int, s *models.Space) {
+		if i == 0 {
+			c, _ := s.CommandFind(deleted.Label)
+			s.CommandDelete(c)
+		} else {
+			command := models.Command{
+				Label: added,
+				Code:  tests.RandString(30),
+			}
+			command.Selector = s.Selector.CloneForItem(command.Label)
+			s.CommandAdd(&command, false)
+		}
+	})
+
+	cboxInstance = tests.ReloadCBox(nil)
+
+	s, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.CommandFind(deleted.Label); err == nil {
+		t.Errorf("command deleted by a concurrent writer was restored")
+	}
+	if _, err := s.CommandFind(kept.Label); err != nil {
+		t.Errorf("command not modified by any writer was lost: %v", err)
+	}
+	if _, err := s.CommandFind(added); err != nil {
+		t.Errorf("command added by a concurrent writer was lost: %v", err)
+	}
+}
+
+func TestConcurrentWritersDoNotRestoreDeletedSpaces(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	tests.ReloadCBox(cboxInstance)
+
+	editor := repository.InitRepository("/tmp")
+	spaces, _ := editor.LoadSpaces()
+
+	deleter := repository.InitRepository("/tmp")
+	deleter.LoadSpaces()
+	deleter.Delete(space.Selector)
+
+	for _, s := range spaces {
+		if s.Label == space.Label {
+			createCommand(t, s)
+		}
+	}
+	editor.Save(spaces)
+
+	cboxInstance = tests.ReloadCBox(nil)
+
+	if _, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label); err == nil {
+		t.Errorf("space deleted by a concurrent writer was restored")
+	}
+}
+
+func TestConcurrentWritersKeepEditsOverDeletions(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	edited := createCommand(t, space)
+	cboxInstance = tests.ReloadCBox(cboxInstance)
+
+	code := tests.RandString(30)
+
+	loadConcurrently(t, 2, func(i int, s *models.Space) {
+		c, _ := s.CommandFind(edited.Label)
+		if i == 0 {
+			s.CommandDelete(c)
+		} else {
+			c.Code = code
+		}
+	})
+
+	cboxInstance = tests.ReloadCBox(nil)
+
+	s, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := s.CommandFind(edited.Label)
+	if err != nil {
+		t.Fatalf("command edited by a concurrent writer was deleted: %v", err)
+	}
+	if c.Code != code {
+		t.Errorf("edit done by a concurrent writer was lost: got '%s', expected '%s'", c.Code, code)
+	}
+}
+
+func TestConcurrentWritersKeepBothEdits(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	edited := createCommand(t, space)
+	cboxInstance = tests.ReloadCBox(cboxInstance)
+
+	codes := []string{tests.RandString(30), tests.RandString(30)}
+
+	loadConcurrently(t, 2, func(i int, s *models.Space) {
+		c, _ := s.CommandFind(edited.Label)
+		c.Code = codes[i]
+	})
+
+	cboxInstance = tests.ReloadCBox(nil)
+
+	s, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]bool{}
+	for _, label := range []string{edited.Label, edited.Label + "-theirs"} {
+		c, err := s.CommandFind(label)
+		if err != nil {
+			t.Fatalf("command edited by both concurrent writers not found: %v", err)
+		}
+		found[c.Code] = true
+	}
+
+	for _, code := range codes {
+		if !found[code] {
+			t.Errorf("edit done by a concurrent writer was lost: '%s' not found", code)
+		}
+	}
+}
+
+func TestConcurrentWritersMergeNewSpaces(t *testing.T) {
+	tests.InitializeCBox()
+
+	label := tests.RandString(8)
+
+	writers := []*repository.Repository{repository.InitRepository("/tmp"), repository.InitRepository("/tmp")}
+	spaces := make([][]*models.Space, len(writers))
+	added := make([]*models.Command, len(writers))
+
+	for i, repo := range writers {
+		spaces[i], _ = repo.LoadSpaces()
+
+		space := models.Space{
+			Label:       label,
+			Description: tests.RandString(15),
+		}
+		space.Selector = models.NewSelector(models.TypeUser, "test", space.Label, "")
+		added[i] = createCommand(t, &space)
+
+		spaces[i] = append(spaces[i], &space)
+	}
+
+	for i, repo := range writers {
+		repo.Save(spaces[i])
+	}
+
+	cboxInstance := tests.ReloadCBox(nil)
+
+	s, err := cboxInstance.SpaceFind(models.TypeUser, "test", label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range added {
+		if _, err := s.CommandFind(command.Label); err != nil {
+			t.Errorf("command of a space created by a concurrent writer was lost: %v", err)
+		}
+	}
+}
+
+func TestConcurrentWritersMergeEditsOfDifferentFields(t *testing.T) {
+	cboxInstance := tests.InitializeCBox()
+
+	space := tests.CreateSpace(t, cboxInstance)
+	edited := createCommand(t, space)
+	cboxInstance = tests.ReloadCBox(cboxInstance)
+
+	code := tests.RandString(30)
+	description := tests.RandString(15)
+
+	loadConcurrently(t, 2, func(i int, s *models.Space) {
+		c, _ := s.CommandFind(edited.Label)
+		if i == 0 {
+			c.Code = code
+		} else {
+			c.Description = description
+		}
+	})
+
+	cboxInstance = tests.ReloadCBox(nil)
+
+	s, err := cboxInstance.SpaceFind(space.Selector.NamespaceType, space.Selector.Namespace, space.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := s.CommandFind(edited.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Code != code || c.Description != description {
+		t.Errorf("edits of different fields done by concurrent writers not merged: %+v", c)
+	}
+	if _, err := s.CommandFind(edited.Label + "-theirs"); err == nil {
+		t.Errorf("command edited by concurrent writers kept twice although its edits could be merged")
+	}
+}
@@ -0,0 +1,151 @@
+package integration_tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/dplabs/cbox/src/repository"
+	"github.com/dplabs/cbox/tests"
+)
+
+const spacesPath = "/tmp/.cbox/spaces"
+
+func skipUnlessJSONBackend(t *testing.T) {
+	if backend := os.Getenv("CBOX_STORAGE_BACKEND"); backend != "" && backend != repository.StorageBackendJSON {
+		t.Skipf("space files are only used by the '%s' storage backend", repository.StorageBackendJSON)
+	}
+}
+
+func writeSpaceFile(t *testing.T, filename string, content string) string {
+	spacePath := path.Join(spacesPath, filename)
+	if err := ioutil.WriteFile(spacePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return spacePath
+}
+
+func TestLoadingOldSpaceFileUpgradesItKeepingABackup(t *testing.T) {
+	skipUnlessJSONBackend(t)
+	tests.InitializeCBox()
+
+	label := "old-" + tests.RandString(4)
+	spacePath := writeSpaceFile(t, label+".json", `{
+  "id": "@`+label+`",
+  "label": "`+label+`",
+  "description": "",
+  "entries": null,
+  "created_at": 1600000000,
+  "updated_at": 1600000000
+}`)
+
+	cboxInstance := tests.ReloadCBox(nil)
+
+	space, err := cboxInstance.SpaceFind(0, "", label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if space.Entries == nil {
+		t.Error("space entries were not upgraded to an empty list")
+	}
+
+	if _, err := os.Stat(spacePath + ".v0.bak"); err != nil {
+		t.Errorf("backup of the old space file not found: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(spacePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		t.Fatal(err)
+	}
+	if content["schema-version"] != float64(1) {
+		t.Errorf("space file not upgraded: schema-version is %v", content["schema-version"])
+	}
+}
+
+func TestUpgradingSpaceFileNeverOverwritesBackups(t *testing.T) {
+	skipUnlessJSONBackend(t)
+	tests.InitializeCBox()
+
+	label := "old-" + tests.RandString(4)
+	spacePath := writeSpaceFile(t, label+".json", `{
+  "id": "@`+label+`",
+  "label": "`+label+`",
+  "entries": null
+}`)
+	writeSpaceFile(t, label+".json.v0.bak", "previous backup")
+
+	tests.ReloadCBox(nil)
+
+	data, err := ioutil.ReadFile(spacePath + ".v0.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "previous backup" {
+		t.Errorf("existing backup was overwritten: %s", data)
+	}
+
+	data, err = ioutil.ReadFile(spacePath + ".v0.1.bak")
+	if err != nil {
+		t.Fatalf("backup of the old space file not found: %v", err)
+	}
+	if !strings.Contains(string(data), `"entries": null`) {
+		t.Errorf("backup does not hold the old space file: %s", data)
+	}
+}
+
+func TestCheckReportsEveryProblem(t *testing.T) {
+	skipUnlessJSONBackend(t)
+	tests.InitializeCBox()
+
+	repo := repository.InitRepository("/tmp")
+	if problems := repo.Check(); len(problems) != 0 {
+		t.Fatalf("fresh repository should have no problems, found: %v", problems)
+	}
+
+	label := "broken-" + tests.RandString(4)
+	defer func() {
+		for _, f := range []string{label + "-invalid.json", label + "-future.json", label + ".json"} {
+			os.Remove(path.Join(spacesPath, f))
+		}
+	}()
+
+	writeSpaceFile(t, label+"-invalid.json", `{ "id": `)
+	writeSpaceFile(t, label+"-future.json", `{ "schema-version": 99, "id": "@`+label+`-future" }`)
+	writeSpaceFile(t, label+".json", `{
+  "schema-version": 1,
+  "id": "@another-space",
+  "label": "`+label+`",
+  "entries": [
+    { "id": "cmd@another-space", "label": "cmd", "code": "" },
+    { "id": "cmd@another-space", "label": "cmd", "code": "ls" }
+  ]
+}`)
+
+	problems := repo.Check()
+
+	expected := []string{
+		label + "-invalid.json: could not parse JSON file",
+		label + "-future.json: schema-version 99 is newer",
+		label + ".json: space ID",
+		label + ".json: duplicated command",
+		label + ".json: command 'cmd' has no code",
+	}
+	for _, e := range expected {
+		found := false
+		for _, problem := range problems {
+			if strings.Contains(problem.Error(), e) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected problem '%s' not reported, got: %v", e, problems)
+		}
+	}
+}
@@ -105,10 +105,10 @@ func TestCommandTimestamps(t *testing.T) {
 		t.Errorf("space update and last command creation timestamps should be the same")
 	}
 
-	previousLabel := cmd.Label
+	previous := *cmd
 	cmd.Label = cmd.Label + "-update"
 
-	err = space.CommandEdit(cmd, previousLabel)
+	err = space.CommandEdit(cmd, previous, "")
 
 	if err != nil {
 		t.Fatalf("failed to rename command: %v", err)
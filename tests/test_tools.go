@@ -2,6 +2,8 @@ package tests
 
 import (
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -108,3 +110,16 @@ func AssertOutputContains(t *testing.T, expected string, msg string) {
 		t.Errorf("%s: %s", msg, tty.MockedOutput)
 	}
 }
+
+// FakeEditor sets $EDITOR to a shell script, receiving the file to edit as $1, and returns the directory
+// holding it so the script can leave marks there
+func FakeEditor(t *testing.T, script string) string {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "editor.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("could not create fake editor: %v", err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", path)
+	return dir
+}